@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/config"
+	"github.com/Sterrenhemel/ddl2struct/pkg/parser"
+)
+
+// loadConfig reads the --config file and fills the flags left unset with its
+// project settings. It returns nil when there is no config file.
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	if cfgFile == "" {
+		return nil, nil
+	}
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := parser.CheckProjectConfig(cfg); err != nil {
+		return nil, err
+	}
+	flags := cmd.Flags()
+	for _, setting := range []struct {
+		flag  string
+		value string
+		dest  *string
+	}{
+		{"output", cfg.Output, &outputPath},
+		{"package", cfg.Package, &packageName},
+		{"nullable", cfg.Nullable, &nullable},
+		{"null-type", cfg.NullType, &nullType},
+		{"tags", cfg.Tags, &tagSpec},
+		{"collisions", cfg.Collisions, &collisions},
+		{"naming-report", cfg.NamingReport, &namingReport},
+	} {
+		if setting.value != "" && !flags.Changed(setting.flag) {
+			*setting.dest = setting.value
+		}
+	}
+	for _, setting := range []struct {
+		flag  string
+		value *bool
+		dest  *bool
+	}{
+		{"golint-names", cfg.GolintNames, &golintNames},
+		{"pinyin", cfg.Pinyin, &pinyin},
+	} {
+		if setting.value != nil && !flags.Changed(setting.flag) {
+			*setting.dest = *setting.value
+		}
+	}
+	initialisms = append(append([]string(nil), cfg.Initialisms...), initialisms...)
+	// --type flags come last, so that they win over the config.
+	keys := make([]string, 0, len(cfg.Types))
+	for key := range cfg.Types {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	mappings := make([]string, 0, len(keys)+len(typeMap))
+	for _, key := range keys {
+		mappings = append(mappings, key+"="+cfg.Types[key])
+	}
+	typeMap = append(mappings, typeMap...)
+	return cfg, nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// stdinInput is the --input value that reads the DDL from standard input.
+const stdinInput = "-"
+
+// input is a DDL file of a run.
+type input struct {
+	Path string // file path, or stdinInput
+	// OutputName is the name, without .go, of the file generated for the
+	// input in an output directory. Files found in subdirectories of an
+	// input directory are named after their relative path, e.g. shop_orders
+	// for shop/orders.sql, so that names stay unique.
+	OutputName string
+}
+
+// Name returns the name of the input in the generated files and messages.
+func (in input) Name() string {
+	if in.Path == stdinInput {
+		return "<stdin>"
+	}
+	return in.Path
+}
+
+func (in input) read() ([]byte, error) {
+	if in.Path == stdinInput {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(in.Path)
+}
+
+// resolveInputs expands the --input values into the files of a run: - for
+// stdin, a .sql file, a directory whose .sql files are taken (walked when
+// recursive) or a glob pattern. Inputs come in the order of the values, the
+// files of a directory or pattern in lexical order; a file named twice is
+// taken once.
+func resolveInputs(values []string, recursive bool) ([]input, error) {
+	var inputs []input
+	seen := make(map[string]bool)
+	add := func(in input) {
+		if in.Path != stdinInput {
+			in.Path = filepath.Clean(in.Path)
+		}
+		if !seen[in.Path] {
+			seen[in.Path] = true
+			inputs = append(inputs, in)
+		}
+	}
+	for _, value := range values {
+		if value == stdinInput {
+			add(input{Path: stdinInput, OutputName: "stdin"})
+			continue
+		}
+		paths := []string{value}
+		if strings.ContainsAny(value, "*?[") {
+			matches, err := filepath.Glob(value)
+			if err != nil {
+				return nil, errors.Annotatef(err, "input pattern %s", value)
+			}
+			if len(matches) == 0 {
+				return nil, errors.Errorf("no input matches %s", value)
+			}
+			sort.Strings(matches)
+			paths = matches
+		}
+		for _, path := range paths {
+			s, err := os.Stat(path)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if !s.IsDir() {
+				add(input{Path: path, OutputName: outputName(filepath.Base(path))})
+				continue
+			}
+			files, err := dirInputs(path, recursive)
+			if err != nil {
+				return nil, err
+			}
+			for _, file := range files {
+				add(file)
+			}
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, errors.New("no input .sql file")
+	}
+	return inputs, nil
+}
+
+// dirInputs returns the .sql files of dir in lexical order, including those
+// of its subdirectories when recursive.
+func dirInputs(dir string, recursive bool) ([]input, error) {
+	var inputs []input
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".sql" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		inputs = append(inputs, input{Path: path, OutputName: outputName(rel)})
+		return nil
+	})
+	return inputs, errors.Trace(err)
+}
+
+// outputName returns the generated file name, without .go, of the input at
+// the relative path rel.
+func outputName(rel string) string {
+	rel = strings.TrimSuffix(rel, filepath.Ext(rel))
+	return strings.ReplaceAll(filepath.ToSlash(rel), "/", "_")
+}
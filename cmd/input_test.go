@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveInputs(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"a.sql", "b.sql", "notes.txt", "shop/orders.sql", "shop/deep/items.sql", "empty/readme.md"} {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("CREATE TABLE t (id int);"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	at := func(file string) string {
+		return filepath.Join(dir, filepath.FromSlash(file))
+	}
+	for _, test := range []struct {
+		name      string
+		values    []string
+		recursive bool
+		inputs    []input
+		err       string
+	}{
+		{
+			name:   "file",
+			values: []string{at("shop/orders.sql")},
+			inputs: []input{{at("shop/orders.sql"), "orders"}},
+		},
+		{
+			name:   "directory",
+			values: []string{dir},
+			inputs: []input{{at("a.sql"), "a"}, {at("b.sql"), "b"}},
+		},
+		{
+			name:      "recursive directory",
+			values:    []string{dir},
+			recursive: true,
+			inputs: []input{
+				{at("a.sql"), "a"},
+				{at("b.sql"), "b"},
+				{at("shop/deep/items.sql"), "shop_deep_items"},
+				{at("shop/orders.sql"), "shop_orders"},
+			},
+		},
+		{
+			name:   "glob",
+			values: []string{filepath.Join(dir, "*", "*.sql")},
+			inputs: []input{{at("shop/orders.sql"), "orders"}},
+		},
+		{
+			name:   "stdin",
+			values: []string{stdinInput},
+			inputs: []input{{stdinInput, "stdin"}},
+		},
+		{
+			name:   "repeated, in the order given, each file once",
+			values: []string{at("b.sql"), dir, at("a.sql"), stdinInput},
+			inputs: []input{{at("b.sql"), "b"}, {at("a.sql"), "a"}, {stdinInput, "stdin"}},
+		},
+		{
+			name:   "no match",
+			values: []string{filepath.Join(dir, "*.ddl")},
+			err:    "no input matches",
+		},
+		{
+			name:   "missing file",
+			values: []string{at("c.sql")},
+			err:    "no such file or directory",
+		},
+		{
+			name:   "no .sql file",
+			values: []string{at("empty")},
+			err:    "no input .sql file",
+		},
+	} {
+		inputs, err := resolveInputs(test.values, test.recursive)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: got %v, want %s", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if !reflect.DeepEqual(inputs, test.inputs) {
+			t.Errorf("%s: got %v, want %v", test.name, inputs, test.inputs)
+		}
+	}
+}
+
+func TestReadStdin(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "stdin.sql")
+	if err := ioutil.WriteFile(file, []byte("CREATE TABLE t (id int);"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdin, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	defer func(saved *os.File) { os.Stdin = saved }(os.Stdin)
+	os.Stdin = stdin
+
+	in := input{Path: stdinInput, OutputName: "stdin"}
+	sql, err := in.read()
+	if err != nil || string(sql) != "CREATE TABLE t (id int);" {
+		t.Errorf("got %q, %v", sql, err)
+	}
+	if name := in.Name(); name != "<stdin>" {
+		t.Errorf("got name %s, want <stdin>", name)
+	}
+}
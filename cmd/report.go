@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"golang.org/x/text/width"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/parser"
+)
+
+// writeNamingReport writes the table of renamings to file, or to standard
+// error when file is -, as standard output holds the generated code.
+func writeNamingReport(file string, renamings []parser.Renaming) error {
+	rows := [][]string{{"TABLE", "STRUCT", "RULE"}}
+	for _, renaming := range renamings {
+		rows = append(rows, []string{renaming.TableName, renaming.StructName, renaming.Rule})
+	}
+	report := formatColumns(rows)
+	if file == "-" {
+		_, err := os.Stderr.Write(report)
+		return errors.Trace(err)
+	}
+	return errors.Trace(ioutil.WriteFile(file, report, 0644))
+}
+
+// formatColumns aligns the cells of rows in columns two spaces apart, as
+// text/tabwriter does, but by display width rather than by rune, so that
+// table and struct names in Chinese, which take two columns of a terminal
+// per character, stay aligned. The last cell of a row is not padded.
+func formatColumns(rows [][]string) []byte {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row[:len(row)-1] {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := displayWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	var b bytes.Buffer
+	for _, row := range rows {
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+2))
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// displayWidth returns the number of terminal columns s takes: two for the
+// wide and fullwidth East Asian characters, one for the others.
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/parser"
+)
+
+func TestNamingReport(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "report.txt")
+	err := writeNamingReport(file, []parser.Renaming{
+		{TableName: "t_admin_roles", StructName: "AdminRole", Rule: "table_names.1: strip \"^t_\", singular"},
+		{TableName: "用户表", StructName: "YongHuBiao", Rule: "camel case"},
+		{TableName: "users", StructName: "用户", Rule: "tables.users.name"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"TABLE          STRUCT      RULE\n" +
+		"t_admin_roles  AdminRole   table_names.1: strip \"^t_\", singular\n" +
+		"用户表         YongHuBiao  camel case\n" +
+		"users          用户        tables.users.name\n"
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	for _, test := range []struct {
+		s     string
+		width int
+	}{
+		{"", 0},
+		{"users", 5},
+		{"用户表", 6},
+		{"user名称", 8},
+		{"ｕｓｅｒ", 8}, // fullwidth
+		{"ｶﾅ", 2},   // halfwidth
+	} {
+		if got := displayWidth(test.s); got != test.width {
+			t.Errorf("%q: got %d, want %d", test.s, got, test.width)
+		}
+	}
+}
@@ -1,16 +1,14 @@
 package cmd
 
 import (
-	"bytes"
 	"fmt"
-	"go/format"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"text/template"
-
-	"github.com/iancoleman/strcase"
+	"sort"
+	"strings"
 
+	"github.com/Sterrenhemel/ddl2struct/pkg/naming"
 	"github.com/Sterrenhemel/ddl2struct/pkg/parser"
 	_ "github.com/Sterrenhemel/ddl2struct/pkg/parser_driver"
 	"github.com/Sterrenhemel/ddl2struct/pkg/tpl"
@@ -21,9 +19,35 @@ import (
 
 var cfgFile string
 var (
-	inputPath   string
+	inputPaths  []string
+	recursive   bool
 	outputPath  string
 	packageName string
+	nullable    string
+	nullType    string
+	typeMap     []string
+	boolTinyInt bool
+	constructor bool
+	enumTypes   bool
+	decimalType string
+	timeType    string
+	association bool
+	validate    bool
+)
+
+var (
+	templateFiles []string
+	templateDir   string
+	renderMode    string
+	tagSpec       string
+)
+
+var (
+	golintNames  bool
+	initialisms  []string
+	pinyin       bool
+	collisions   string
+	namingReport string
 )
 
 var rootCmd = &cobra.Command{
@@ -42,70 +66,236 @@ func Execute() {
 
 func init() {
 	flag := rootCmd.PersistentFlags()
-	flag.StringVarP(&inputPath, "input", "i", "", `sql file path`)
+	flag.StringVar(&cfgFile, "config", "", "YAML or TOML file of project, table and column settings")
+	flag.StringArrayVarP(&inputPaths, "input", "i", nil, `sql file, directory or glob pattern, or - for stdin; may be repeated`)
+	flag.BoolVarP(&recursive, "recursive", "r", false, "also read the .sql files of the subdirectories of input directories")
 	flag.StringVarP(&outputPath, "output", "o", "", `output file path`)
 	flag.StringVarP(&packageName, "package", "p", "", "go file package")
+	flag.StringVar(&nullable, "nullable", "none", "nullable column type: none, pointer, sql or generic (sql.Null[T], needs Go 1.22)")
+	flag.StringArrayVar(&typeMap, "type", nil, `override a type mapping, as "int unsigned=uint64" or "decimal=github.com/shopspring/decimal.Decimal"`)
+	flag.BoolVar(&boolTinyInt, "tinyint1-bool", false, "map tinyint(1) and boolean columns to bool")
+	flag.BoolVar(&enumTypes, "enum-types", true, "generate a Go type for every enum and set column")
+	flag.BoolVar(&constructor, "constructor", false, "generate a New function that applies the column defaults")
+	flag.StringVar(&decimalType, "decimal", "exact", "Go type of decimal columns: exact (dbtypes.Decimal), shopspring or float64")
+	flag.StringVar(&timeType, "time-type", "time", "Go type of date, datetime and timestamp columns: time (time.Time) or mysql (dbtypes.MySQLTime, zero-date safe)")
+	flag.BoolVar(&validate, "validate", false, "generate a Validate method that rejects the values MySQL would reject for each column")
+	flag.BoolVar(&association, "associations", false, "generate gorm belongs-to and has-many fields from foreign keys")
+	flag.StringVar(&nullType, "null-type", parser.DefaultGenericNullType, "generic type used by --nullable=generic, as import/path.Name")
+	flag.StringVar(&tagSpec, "tags", parser.DefaultTags, "struct tags of the fields: json, yaml, mapstructure, db, sqlx, bun, xorm, gorm or validate, each followed by a naming style (snake, camel, pascal, kebab or column) and options, e.g. json:camel:omitempty,db,validate")
+	flag.BoolVar(&golintNames, "golint-names", false, "spell the golint initialisms in capitals in Go names, e.g. UserID and URL")
+	flag.StringArrayVar(&initialisms, "initialism", nil, "extra initialism spelled in capitals in Go names, e.g. SKU; may be repeated")
+	flag.BoolVar(&pinyin, "pinyin", false, "transliterate Chinese characters of table and column names to pinyin in Go names")
+	flag.StringVar(&collisions, "collisions", "error", "what to do when two Go names are the same: error or suffix (UserId, UserId2)")
+	flag.StringVar(&namingReport, "naming-report", "", "file to write how every table was named to, - for standard error")
+	flag.StringArrayVar(&templateFiles, "template", nil, "text/template file to render instead of the built-in template; may be repeated")
+	flag.StringVar(&templateDir, "template-dir", "", "directory of .tmpl files to render instead of the built-in template; those starting with _ are partials")
+	flag.StringVar(&renderMode, "render", "file", "what each template renders: file (every output file), table (every table) or schema (all tables at once)")
 }
 
 func runCommand(cmd *cobra.Command, args []string) {
-	s, err := os.Stat(inputPath)
+	cfg, err := loadConfig(cmd)
 	if err != nil {
 		logutil.BgSLogger().Fatal(err)
-	} else {
-		if s.IsDir() {
-			files, err := ioutil.ReadDir(inputPath)
-			if err != nil {
-				logutil.BgSLogger().Fatal(err)
-			}
-			for _, file := range files {
-				if filepath.Ext(file.Name()) == ".sql" {
-					name := filepath.Join(inputPath, file.Name())
-					parseFile(name)
-				}
-			}
+	}
+	inputs, err := resolveInputs(inputPaths, recursive)
+	if err != nil {
+		logutil.BgSLogger().Fatal(err)
+	}
+	mode, err := tpl.ParseMode(renderMode)
+	if err != nil {
+		logutil.BgSLogger().Fatal(err)
+	}
+	templates := []*tpl.Template{tpl.Builtin()}
+	if len(templateFiles) > 0 || templateDir != "" {
+		if templates, err = tpl.Load(templateFiles, templateDir); err != nil {
+			logutil.BgSLogger().Fatal(err)
 		}
 	}
+	// All inputs are parsed in one session, so that statements and foreign
+	// keys refer to tables of other files and tables routed to the same
+	// output file are generated together.
+	ddlParser, err := newParser()
+	if err != nil {
+		logutil.BgSLogger().Fatal(err)
+	}
+	if cfg != nil {
+		if err := ddlParser.SetConfig(cfg); err != nil {
+			logutil.BgSLogger().Fatal(err)
+		}
+	}
+	parserInputs := make([]parser.Input, 0, len(inputs))
+	for _, in := range inputs {
+		sql, err := in.read()
+		if err != nil {
+			logutil.BgSLogger().Fatal(err)
+		}
+		parserInputs = append(parserInputs, parser.Input{File: in.Name(), OutputName: in.OutputName, SQL: string(sql)})
+	}
+	if err := ddlParser.ParseInputs(parserInputs...); err != nil {
+		logutil.BgSLogger().Fatal(err)
+	}
+	for _, warning := range ddlParser.Warnings {
+		logutil.BgSLogger().Warn(warning)
+	}
+	if namingReport != "" {
+		if err := writeNamingReport(namingReport, ddlParser.Renamings); err != nil {
+			logutil.BgSLogger().Fatal(err)
+		}
+	}
+	if association {
+		ddlParser.AddAssociations(ddlParser.Relationships())
+	}
+	generate(ddlParser, templates, mode)
 }
 
-func parseFile(filepath string) {
-	sql, err := ioutil.ReadFile(filepath)
+// newParser returns a parser set up from the flags, or an error naming the
+// flag whose value is wrong.
+func newParser() (*parser.DDLParser, error) {
+	ddlParser := parser.New("", outputPath, packageName)
+	strategy, err := parser.ParseNullableStrategy(nullable)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("--nullable: %v", err)
 	}
-	ddlParser := parser.New(filepath, outputPath, packageName)
-	if err := ddlParser.Parse(string(sql)); err != nil {
-		panic(err)
+	ddlParser.Nullable = strategy
+	if err := ddlParser.SetGenericNullType(nullType); err != nil {
+		return nil, fmt.Errorf("--null-type: %v", err)
+	}
+	ddlParser.TinyIntAsBool = boolTinyInt
+	ddlParser.Constructors = constructor
+	ddlParser.Validate = validate
+	ddlParser.EnumTypes = enumTypes
+	if err := ddlParser.SetDecimalType(decimalType); err != nil {
+		return nil, fmt.Errorf("--decimal: %v", err)
 	}
+	if err := ddlParser.SetTimeType(timeType); err != nil {
+		return nil, fmt.Errorf("--time-type: %v", err)
+	}
+	if err := ddlParser.SetTags(tagSpec); err != nil {
+		return nil, fmt.Errorf("--tags: %v", err)
+	}
+	names := initialisms
+	if golintNames {
+		names = append(append([]string(nil), naming.CommonInitialisms...), initialisms...)
+	}
+	ddlParser.Naming = naming.NewNamer(names, pinyin)
+	if ddlParser.Collisions, err = parser.ParseCollisionStrategy(collisions); err != nil {
+		return nil, fmt.Errorf("--collisions: %v", err)
+	}
+	for _, mapping := range typeMap {
+		i := strings.LastIndex(mapping, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("--type: type mapping must look like sqltype=gotype: %s", mapping)
+		}
+		if err := ddlParser.SetType(strings.TrimSpace(mapping[:i]), strings.TrimSpace(mapping[i+1:])); err != nil {
+			return nil, fmt.Errorf("--type: %v", err)
+		}
+	}
+	return ddlParser, nil
+}
 
-	//structFiles, err := ddlParser.ToStructs(true)
-	//if err != nil {
-	//	panic(err)
-	//}
+// rendering is a template to execute with data. It renders the file base of
+// dir, with the extension of the template, unless the template names the file.
+type rendering struct {
+	template  *tpl.Template
+	data      tpl.TemplateVar
+	dir, base string
+}
 
+func generate(ddlParser *parser.DDLParser, templates []*tpl.Template, mode tpl.Mode) {
+	fileNames := make([]string, 0, len(ddlParser.FileTables))
 	for fileName := range ddlParser.FileTables {
-		t := template.Must(template.New(fileName).Funcs(map[string]interface{}{
-			"mapExists": mapExists,
-			"ToCamel":   strcase.ToCamel,
-			"ToSnake":   strcase.ToSnake,
-		}).Parse(tpl.TableTemplate))
-		buf := &bytes.Buffer{}
-		err := t.Execute(buf, TemplateVar{
-			InputFile:   filepath,
-			PackageName: packageName,
-			Imports:     ddlParser.FileImports[fileName],
-			Structs:     ddlParser.FileTables[fileName],
-			WithTag:     true,
-			//FileContent: string(fileBytes),
-		})
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	relationships := ddlParser.Relationships()
+	var renderings []rendering
+	render := func(data tpl.TemplateVar, dir, base string) {
+		data.Relationships = relationships
+		if len(data.InputFiles) > 0 {
+			data.InputFile = data.InputFiles[0]
+		}
+		data.WithConstructor = constructor
+		data.WithTag = len(ddlParser.Tags) > 0
+		data.TagString = tagSpec
+		for _, t := range templates {
+			renderings = append(renderings, rendering{template: t, data: data, dir: dir, base: base})
+		}
+	}
+	switch mode {
+	case tpl.ModeFile:
+		for _, fileName := range fileNames {
+			data := tpl.NewTemplateVar(mode, ddlParser.FileTables[fileName])
+			data.InputFiles = ddlParser.FileInputs(fileName)
+			data.PackageName = ddlParser.FilePackage(fileName)
+			data.Imports = ddlParser.FileImports[fileName]
+			render(data, filepath.Dir(fileName), stem(fileName))
+		}
+	case tpl.ModeTable:
+		for _, fileName := range fileNames {
+			for _, table := range tpl.NewTemplateVar(mode, ddlParser.FileTables[fileName]).Tables {
+				data := tpl.NewTemplateVar(mode, map[string]*parser.Table{table.TableName: table})
+				data.InputFiles = table.InputFiles
+				data.PackageName = ddlParser.FilePackage(fileName)
+				data.Imports = ddlParser.TableImports(table)
+				render(data, filepath.Dir(fileName), table.TableName)
+			}
+		}
+	case tpl.ModeSchema:
+		structs := make(map[string]*parser.Table)
+		imports := make(map[string]string)
+		var packageNames []string
+		for _, fileName := range fileNames {
+			for tableName, table := range ddlParser.FileTables[fileName] {
+				structs[tableName] = table
+			}
+			for alias, importPath := range ddlParser.FileImports[fileName] {
+				imports[alias] = importPath
+			}
+			if pkg := ddlParser.FilePackage(fileName); len(packageNames) == 0 || packageNames[0] != pkg {
+				packageNames = append(packageNames, pkg)
+			}
+		}
+		if len(packageNames) > 1 {
+			logutil.BgSLogger().Fatalf("the schema render mode needs all tables in one package, got %s", strings.Join(packageNames, ", "))
+		}
+		data := tpl.NewTemplateVar(mode, structs)
+		data.InputFiles = ddlParser.Inputs()
+		data.PackageName = packageName
+		if len(packageNames) == 1 {
+			data.PackageName = packageNames[0]
+		}
+		data.Imports = imports
+		if info, err := os.Stat(outputPath); err == nil && info.IsDir() {
+			render(data, outputPath, "")
+		} else {
+			render(data, filepath.Dir(outputPath), stem(outputPath))
+		}
+	}
+
+	rendered := make(map[string]string)
+	for _, r := range renderings {
+		base := r.base
+		if base == "" {
+			base = r.template.Stem()
+		}
+		fileName, err := r.template.FileName(r.data, r.dir, base)
 		if err != nil {
-			panic(err)
+			logutil.BgSLogger().Fatal(err)
 		}
-		content := buf.Bytes()
-		source, err := format.Source(content)
+		if other, ok := rendered[fileName]; ok && outputPath != "" {
+			logutil.BgSLogger().Fatalf("templates %s and %s both render %s, define a filename template", other, r.template.Name, fileName)
+		}
+		rendered[fileName] = r.template.Name
+		source, err := r.template.Execute(fileName, r.data)
 		if err != nil {
-			return
+			logutil.BgSLogger().Fatal(err)
 		}
-		if fileName != "" {
+		if outputPath != "" {
+			// Tables the config puts in another package go to a subdirectory.
+			if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+				panic(err)
+			}
 			if err := ioutil.WriteFile(fileName, source, 0644); err != nil {
 				panic(err)
 			}
@@ -114,21 +304,10 @@ func parseFile(filepath string) {
 	}
 }
 
-type TemplateVar struct {
-	InputFile   string
-	PackageName string
-	Imports     map[string]string
-	Structs     map[string]*parser.Table
-	WithTag     bool
-	TagString   string
-	FileContent string
-}
-
-func mapExists(v TemplateVar) bool {
-	if v.Imports == nil || len(v.Imports) == 0 {
-		return false
-	}
-	return true
+// stem returns the base name of fileName without its extension.
+func stem(fileName string) string {
+	base := filepath.Base(fileName)
+	return strings.TrimSuffix(base, filepath.Ext(base))
 }
 
 func generateFileFromBytes(structBytes []byte) {
@@ -4,11 +4,14 @@ go 1.17
 
 require (
 	github.com/iancoleman/strcase v0.2.0
+	github.com/pelletier/go-toml/v2 v2.0.9
 	github.com/pingcap/errors v0.11.5-0.20211224045212-9687c2b0f87c
 	github.com/pingcap/log v0.0.0-20210625125904-98ed8e2eb1c7
 	github.com/pingcap/parser v0.0.0-20200623164729-3a18f1e5dceb
 	github.com/spf13/cobra v1.3.1-0.20220216221717-7cabfeb8f837
 	go.uber.org/zap v1.18.1
+	golang.org/x/text v0.3.7
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -17,6 +20,5 @@ require (
 	github.com/spf13/pflag v1.0.5 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
-	golang.org/x/text v0.3.7 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0 // indirect
 )
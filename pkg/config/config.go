@@ -0,0 +1,369 @@
+// Package config reads the project configuration of ddl2struct, a YAML or
+// TOML file that sets the generation options of the whole project, of a table
+// or of a column.
+package config
+
+import (
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// Config is the configuration of a project. Command line flags win over the
+// project settings.
+type Config struct {
+	Package  string            `yaml:"package" toml:"package"`     // as --package
+	Output   string            `yaml:"output" toml:"output"`       // as --output
+	Nullable string            `yaml:"nullable" toml:"nullable"`   // as --nullable
+	NullType string            `yaml:"null_type" toml:"null_type"` // as --null-type
+	Types    map[string]string `yaml:"types" toml:"types"`         // as --type, e.g. "int unsigned": uint64
+	Tags     string            `yaml:"tags" toml:"tags"`           // as --tags
+	// GolintNames, Initialisms, Pinyin and Collisions are as the naming
+	// flags; initialisms come before those of --initialism.
+	GolintNames *bool    `yaml:"golint_names" toml:"golint_names"`
+	Initialisms []string `yaml:"initialisms" toml:"initialisms"`
+	Pinyin      *bool    `yaml:"pinyin" toml:"pinyin"`
+	Collisions  string   `yaml:"collisions" toml:"collisions"`
+	// TableNames are the rules naming the structs of the tables that Tables
+	// does not name. The first rule matching a table applies.
+	TableNames   []*TableNameRule `yaml:"table_names" toml:"table_names"`
+	NamingReport string           `yaml:"naming_report" toml:"naming_report"` // as --naming-report
+	// WithTags controls whether struct fields have the tags of --tags; they
+	// do unless set to false.
+	WithTags *bool `yaml:"with_tags" toml:"with_tags"`
+	// Include and Exclude are patterns of the tables to generate, as matched
+	// by path.Match. Tables must match an Include pattern, when there are
+	// any, and no Exclude pattern.
+	Include []string          `yaml:"include" toml:"include"`
+	Exclude []string          `yaml:"exclude" toml:"exclude"`
+	Tables  map[string]*Table `yaml:"tables" toml:"tables"` // by table name
+	// Columns are keyed by "table.column", where both parts are patterns,
+	// e.g. "*.created_at".
+	Columns map[string]*Column `yaml:"columns" toml:"columns"`
+
+	file  string
+	lines []keyLine
+}
+
+// Table is the configuration of a table.
+type Table struct {
+	Name string `yaml:"name" toml:"name"` // struct name
+	// Output is the file the table is generated in, relative to the output
+	// directory, or to the directory of the output file.
+	Output string `yaml:"output" toml:"output"`
+	// Package is the package the table is generated in, in the subdirectory
+	// of the output directory named after it unless Output is set.
+	Package  string             `yaml:"package" toml:"package"`
+	Nullable string             `yaml:"nullable" toml:"nullable"`
+	Types    map[string]string  `yaml:"types" toml:"types"` // as Config.Types, for the columns of the table
+	WithTags *bool              `yaml:"with_tags" toml:"with_tags"`
+	Columns  map[string]*Column `yaml:"columns" toml:"columns"` // by column name
+}
+
+// TableNameRule derives struct names from table names: the table name is
+// stripped, singularised, camel cased and framed by Prefix and Suffix.
+type TableNameRule struct {
+	// Match is the regular expression of the table names the rule applies
+	// to, all of them when empty.
+	Match string `yaml:"match" toml:"match"`
+	// Strip is a regular expression whose matches are removed from the table
+	// name, e.g. "^t_" or "_[0-9]+$".
+	Strip    string `yaml:"strip" toml:"strip"`
+	Singular bool   `yaml:"singular" toml:"singular"` // singularises the last word
+	Prefix   string `yaml:"prefix" toml:"prefix"`
+	Suffix   string `yaml:"suffix" toml:"suffix"`
+
+	match, strip *regexp.Regexp
+}
+
+// Column is the configuration of a column.
+type Column struct {
+	Name     string            `yaml:"name" toml:"name"` // field name
+	Type     string            `yaml:"type" toml:"type"` // Go type, as a predeclared type or import/path.Name
+	Nullable string            `yaml:"nullable" toml:"nullable"`
+	Tags     map[string]string `yaml:"tags" toml:"tags"` // extra struct tags, e.g. validate: "email"
+}
+
+// keyLine is the line of a key of the file.
+type keyLine struct {
+	path []string
+	line int
+}
+
+// Load reads the configuration file named file, whose format is told by its
+// extension: .yaml, .yml or .toml. Unknown keys are errors, reported with
+// their line.
+func Load(file string) (*Config, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg := &Config{file: file}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		err = cfg.decodeYAML(data)
+	case ".toml":
+		err = cfg.decodeTOML(data)
+	default:
+		return nil, errors.Errorf("unknown config file format :%s, use .yaml, .yml or .toml", file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Errorf returns an error about the setting at key path, e.g.
+// "tables", "users", "nullable", located at its line when known.
+func (cfg *Config) Errorf(path []string, format string, args ...interface{}) error {
+	location := cfg.file
+	if line := cfg.line(path); line > 0 {
+		location = location + ":" + strconv.Itoa(line)
+	}
+	return errors.Errorf("%s: %s: %s", location, strings.Join(path, "."), fmt.Sprintf(format, args...))
+}
+
+func (cfg *Config) line(path []string) int {
+	for _, key := range cfg.lines {
+		if equalPath(key.path, path) {
+			return key.line
+		}
+	}
+	return 0
+}
+
+func equalPath(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// validate checks the settings that do not depend on the parser.
+func (cfg *Config) validate() error {
+	for _, list := range []struct {
+		key      string
+		patterns []string
+	}{{"include", cfg.Include}, {"exclude", cfg.Exclude}} {
+		for _, pattern := range list.patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return cfg.Errorf([]string{list.key}, "bad pattern %q", pattern)
+			}
+		}
+	}
+	for i, rule := range cfg.TableNames {
+		if err := cfg.validateTableNameRule([]string{"table_names", strconv.Itoa(i)}, rule); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(cfg.Columns) {
+		keyPath := []string{"columns", key}
+		dot := strings.LastIndex(key, ".")
+		if dot <= 0 || dot == len(key)-1 {
+			return cfg.Errorf(keyPath, "key must look like table.column")
+		}
+		for _, pattern := range []string{key[:dot], key[dot+1:]} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return cfg.Errorf(keyPath, "bad pattern %q", pattern)
+			}
+		}
+		if err := cfg.validateColumn(keyPath, cfg.Columns[key]); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(cfg.Tables) {
+		table := cfg.Tables[name]
+		if table == nil {
+			continue
+		}
+		keyPath := []string{"tables", name}
+		if table.Name != "" && !token.IsIdentifier(table.Name) {
+			return cfg.Errorf(append(keyPath, "name"), "%q is not a Go identifier", table.Name)
+		}
+		if table.Package != "" && !token.IsIdentifier(table.Package) {
+			return cfg.Errorf(append(keyPath, "package"), "%q is not a Go package name", table.Package)
+		}
+		for _, column := range sortedKeys(table.Columns) {
+			if err := cfg.validateColumn(append(keyPath, "columns", column), table.Columns[column]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (cfg *Config) validateTableNameRule(keyPath []string, rule *TableNameRule) error {
+	if rule == nil {
+		return cfg.Errorf(keyPath, "empty rule")
+	}
+	var err error
+	for _, expr := range []struct {
+		key     string
+		pattern string
+		dest    **regexp.Regexp
+	}{{"match", rule.Match, &rule.match}, {"strip", rule.Strip, &rule.strip}} {
+		if expr.pattern == "" {
+			continue
+		}
+		if *expr.dest, err = regexp.Compile(expr.pattern); err != nil {
+			return cfg.Errorf(append(keyPath, expr.key), "bad regular expression %q", expr.pattern)
+		}
+	}
+	if rule.Prefix != "" && !token.IsIdentifier(rule.Prefix) {
+		return cfg.Errorf(append(keyPath, "prefix"), "%q is not a Go identifier", rule.Prefix)
+	}
+	if rule.Suffix != "" && !token.IsIdentifier("X"+rule.Suffix) {
+		return cfg.Errorf(append(keyPath, "suffix"), "%q cannot end a Go identifier", rule.Suffix)
+	}
+	return nil
+}
+
+func (cfg *Config) validateColumn(keyPath []string, column *Column) error {
+	if column == nil {
+		return nil
+	}
+	if column.Name != "" && !token.IsIdentifier(column.Name) {
+		return cfg.Errorf(append(keyPath, "name"), "%q is not a Go identifier", column.Name)
+	}
+	for _, key := range sortedKeys(column.Tags) {
+		if key == "json" || key == "gorm" {
+			return cfg.Errorf(append(keyPath, "tags", key), "tag is generated, use with_tags to leave it out")
+		}
+		if key == "" || strings.ContainsAny(key, " \t\":`") {
+			return cfg.Errorf(append(keyPath, "tags", key), "bad tag key")
+		}
+	}
+	return nil
+}
+
+// File returns the name of the configuration file.
+func (cfg *Config) File() string {
+	if cfg == nil {
+		return ""
+	}
+	return cfg.file
+}
+
+// Included reports whether the table named name is generated.
+func (cfg *Config) Included(name string) bool {
+	if cfg == nil {
+		return true
+	}
+	included := len(cfg.Include) == 0
+	for _, pattern := range cfg.Include {
+		if ok, _ := path.Match(pattern, name); ok {
+			included = true
+			break
+		}
+	}
+	for _, pattern := range cfg.Exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+	return included
+}
+
+// Table returns the configuration of the table named name, empty when it has
+// none.
+func (cfg *Config) Table(name string) Table {
+	if cfg == nil || cfg.Tables[name] == nil {
+		return Table{}
+	}
+	return *cfg.Tables[name]
+}
+
+// TableNameRule returns the first rule of TableNames matching the table named
+// name, and its index, or nil and -1 when none does.
+func (cfg *Config) TableNameRule(name string) (*TableNameRule, int) {
+	if cfg == nil {
+		return nil, -1
+	}
+	for i, rule := range cfg.TableNames {
+		if rule.match == nil || rule.match.MatchString(name) {
+			return rule, i
+		}
+	}
+	return nil, -1
+}
+
+// Stripped returns name without the matches of Strip.
+func (rule *TableNameRule) Stripped(name string) string {
+	if rule.strip == nil {
+		return name
+	}
+	return rule.strip.ReplaceAllString(name, "")
+}
+
+// Column returns the configuration of column of table: the settings of the
+// Columns entries matching table.column, in key order, overridden by those of
+// the column in the Tables entry of table.
+func (cfg *Config) Column(table, column string) Column {
+	var merged Column
+	if cfg == nil {
+		return merged
+	}
+	for _, key := range sortedKeys(cfg.Columns) {
+		dot := strings.LastIndex(key, ".")
+		tableMatch, _ := path.Match(key[:dot], table)
+		columnMatch, _ := path.Match(key[dot+1:], column)
+		if tableMatch && columnMatch {
+			merged = merged.merge(cfg.Columns[key])
+		}
+	}
+	return merged.merge(cfg.Table(table).Columns[column])
+}
+
+// merge returns column overridden by the settings of other.
+func (column Column) merge(other *Column) Column {
+	if other == nil {
+		return column
+	}
+	if other.Name != "" {
+		column.Name = other.Name
+	}
+	if other.Type != "" {
+		column.Type = other.Type
+	}
+	if other.Nullable != "" {
+		column.Nullable = other.Nullable
+	}
+	if len(other.Tags) > 0 {
+		tags := make(map[string]string, len(column.Tags)+len(other.Tags))
+		for key, value := range column.Tags {
+			tags[key] = value
+		}
+		for key, value := range other.Tags {
+			tags[key] = value
+		}
+		column.Tags = tags
+	}
+	return column
+}
+
+// sortedKeys returns the keys of m, a map with string keys, in order.
+func sortedKeys(m interface{}) []string {
+	keys := make([]string, 0)
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
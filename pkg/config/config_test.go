@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes content to a config file named name and returns its path.
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		content string
+		err     string // with %s for the file name
+	}{
+		{"top.yaml", "package: models\nnulable: sql\n", "%s:2: unknown key nulable"},
+		{"table.yaml", "tables:\n  users:\n    name: Account\n    colums:\n      id: {}\n", "%s:4: unknown key tables.users.colums"},
+		{"column.yaml", "tables:\n  users:\n    columns:\n      id:\n        typ: int64\n", "%s:5: unknown key tables.users.columns.id.typ"},
+		{"rule.yaml", "table_names:\n  - strip: '^t_'\n  - sufix: PO\n", "%s:3: unknown key table_names.1.sufix"},
+		{"scalar.yaml", "package:\n  name: models\n", "%s:2: unknown key package.name"},
+		{"top.toml", "package = \"models\"\n\nnulable = \"sql\"\n", "%s:3: unknown key nulable"},
+		{"table.toml", "[tables.users]\nname = \"Account\"\ncolums = {}\n", "%s:3: unknown key tables.users.colums"},
+		{"column.toml", "[tables.users.columns.id]\ntyp = \"int64\"\n", "%s:2: unknown key tables.users.columns.id.typ"},
+		{"rule.toml", "[[table_names]]\nstrip = \"^t_\"\n\n[[table_names]]\nsufix = \"PO\"\n", "%s:5: unknown key table_names.1.sufix"},
+		{"dotted.toml", "tables.users.nam = \"Account\"\n", "%s:1: unknown key tables.users.nam"},
+		{"pattern.yaml", "\ninclude: ['[a-']\n", "%s:2: include: bad pattern \"[a-\""},
+		{"regexp.toml", "[[table_names]]\nmatch = \"(\"\n", "%s:2: table_names.0.match: bad regular expression \"(\""},
+		{"name.yaml", "tables:\n  users:\n    name: 1user\n", "%s:3: tables.users.name: \"1user\" is not a Go identifier"},
+		{"suffix.yaml", "table_names:\n  - suffix: '-x'\n", "%s:2: table_names.0.suffix: \"-x\" cannot end a Go identifier"},
+		{"columns.yaml", "columns:\n  id:\n    type: int64\n", "%s:2: columns.id: key must look like table.column"},
+		{"tag.toml", "[columns.\"*.id\".tags]\njson = \"id\"\n", "%s:2: columns.*.id.tags.json: tag is generated, use with_tags to leave it out"},
+		{"syntax.yaml", "tables: [\n", "%s: yaml: line 1: did not find expected node content"},
+		{"syntax.toml", "tables = [\n", "%s:1: array is incomplete"},
+		{"config.json", "{}", "unknown config file format :%s, use .yaml, .yml or .toml"},
+	} {
+		file := writeConfig(t, test.name, test.content)
+		_, err := Load(file)
+		if want := fmt.Sprintf(test.err, file); err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("%s: got %v, want %s", test.name, err, want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	for _, name := range []string{"ddl2struct.yaml", "ddl2struct.toml"} {
+		content := `
+package: models
+include: ["t_*", "users"]
+exclude: ["*_bak"]
+table_names:
+  - match: "^t_"
+    strip: "^t_"
+    singular: true
+tables:
+  users:
+    name: Account
+    columns:
+      id:
+        type: int64
+columns:
+  "*.id":
+    name: ID
+    tags:
+      db: id
+  "users.*":
+    nullable: pointer
+`
+		if strings.HasSuffix(name, ".toml") {
+			content = `
+package = "models"
+include = ["t_*", "users"]
+exclude = ["*_bak"]
+
+[[table_names]]
+match = "^t_"
+strip = "^t_"
+singular = true
+
+[tables.users]
+name = "Account"
+columns.id.type = "int64"
+
+[columns."*.id"]
+name = "ID"
+tags = { db = "id" }
+
+[columns."users.*"]
+nullable = "pointer"
+`
+		}
+		cfg, err := Load(writeConfig(t, name, content))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if cfg.Package != "models" || cfg.Table("users").Name != "Account" {
+			t.Errorf("%s: got package %s and users named %s", name, cfg.Package, cfg.Table("users").Name)
+		}
+		for table, included := range map[string]bool{"t_roles": true, "users": true, "t_roles_bak": false, "orders": false} {
+			if got := cfg.Included(table); got != included {
+				t.Errorf("%s: %s included: got %v, want %v", name, table, got, included)
+			}
+		}
+		if rule, i := cfg.TableNameRule("t_roles"); rule == nil || i != 0 || rule.Stripped("t_roles") != "roles" {
+			t.Errorf("%s: t_roles: got rule %d %+v", name, i, rule)
+		}
+		if rule, _ := cfg.TableNameRule("users"); rule != nil {
+			t.Errorf("%s: users: got rule %+v, want none", name, rule)
+		}
+		want := Column{Name: "ID", Type: "int64", Nullable: "pointer", Tags: map[string]string{"db": "id"}}
+		if got := cfg.Column("users", "id"); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: users.id: got %+v, want %+v", name, got, want)
+		}
+		want = Column{Name: "ID", Tags: map[string]string{"db": "id"}}
+		if got := cfg.Column("t_roles", "id"); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: t_roles.id: got %+v, want %+v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"github.com/pingcap/errors"
+	"gopkg.in/yaml.v3"
+)
+
+func (cfg *Config) decodeYAML(data []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return errors.Errorf("%s: %v", cfg.file, err)
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+	cfg.yamlLines(root.Content[0], nil)
+	if err := cfg.checkKeys(); err != nil {
+		return err
+	}
+	if err := root.Content[0].Decode(cfg); err != nil {
+		return errors.Errorf("%s: %v", cfg.file, err)
+	}
+	return nil
+}
+
+// yamlLines records the line of the keys of the mapping node and of the
+// mappings nested in it. The items of sequences are keyed by their index.
+func (cfg *Config) yamlLines(node *yaml.Node, path []string) {
+	if node.Kind == yaml.SequenceNode {
+		for i, item := range node.Content {
+			keyPath := append(append([]string(nil), path...), strconv.Itoa(i))
+			cfg.lines = append(cfg.lines, keyLine{path: keyPath, line: item.Line})
+			cfg.yamlLines(item, keyPath)
+		}
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		keyPath := append(append([]string(nil), path...), key.Value)
+		cfg.lines = append(cfg.lines, keyLine{path: keyPath, line: key.Line})
+		cfg.yamlLines(value, keyPath)
+	}
+}
+
+func (cfg *Config) decodeTOML(data []byte) error {
+	if err := cfg.tomlLines(data); err != nil {
+		return err
+	}
+	if err := cfg.checkKeys(); err != nil {
+		return err
+	}
+	if err := toml.NewDecoder(bytes.NewReader(data)).Decode(cfg); err != nil {
+		if decodeErr, ok := err.(*toml.DecodeError); ok {
+			row, _ := decodeErr.Position()
+			return errors.Errorf("%s:%d: %v", cfg.file, row, err)
+		}
+		return errors.Errorf("%s: %v", cfg.file, err)
+	}
+	return nil
+}
+
+// tomlLines records the line of the keys of the document: those of key/value
+// pairs and inline tables, below the table header they follow. The tables of
+// an array are keyed by their index.
+func (cfg *Config) tomlLines(data []byte) error {
+	p := &unstable.Parser{}
+	p.Reset(data)
+	var table []string
+	arrays := make(map[string]int)
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table:
+			table = cfg.tomlKey(p, expr, nil)
+		case unstable.ArrayTable:
+			table = cfg.tomlKey(p, expr, nil)
+			key := strings.Join(table, ".")
+			table = append(table, strconv.Itoa(arrays[key]))
+			arrays[key]++
+			cfg.lines = append(cfg.lines, keyLine{
+				path: append([]string(nil), table...),
+				line: cfg.lines[len(cfg.lines)-1].line, // that of the header
+			})
+		case unstable.KeyValue:
+			cfg.tomlKeyValue(p, expr, table)
+		}
+	}
+	if err := p.Error(); err != nil {
+		if parserErr, ok := err.(*unstable.ParserError); ok {
+			return errors.Errorf("%s:%d: %s", cfg.file, p.Shape(p.Range(parserErr.Highlight)).Start.Line, parserErr.Message)
+		}
+		return errors.Errorf("%s: %v", cfg.file, err)
+	}
+	return nil
+}
+
+// tomlKey records the line of every part of the key of node, a table header
+// or a key/value pair, below path, and returns the full key.
+func (cfg *Config) tomlKey(p *unstable.Parser, node *unstable.Node, path []string) []string {
+	keyPath := append([]string(nil), path...)
+	for it := node.Key(); it.Next(); {
+		part := it.Node()
+		keyPath = append(keyPath, string(part.Data))
+		cfg.lines = append(cfg.lines, keyLine{
+			path: append([]string(nil), keyPath...),
+			line: p.Shape(part.Raw).Start.Line,
+		})
+	}
+	return keyPath
+}
+
+func (cfg *Config) tomlKeyValue(p *unstable.Parser, node *unstable.Node, path []string) {
+	keyPath := cfg.tomlKey(p, node, path)
+	cfg.tomlValue(p, node.Value(), keyPath)
+}
+
+func (cfg *Config) tomlValue(p *unstable.Parser, value *unstable.Node, path []string) {
+	switch value.Kind {
+	case unstable.InlineTable:
+		for it := value.Children(); it.Next(); {
+			cfg.tomlKeyValue(p, it.Node(), path)
+		}
+	case unstable.Array:
+		i := 0
+		for it := value.Children(); it.Next(); i++ {
+			if item := it.Node(); item.Kind == unstable.InlineTable {
+				cfg.tomlValue(p, item, append(append([]string(nil), path...), strconv.Itoa(i)))
+			}
+		}
+	}
+}
+
+// checkKeys fails on the first key, in document order, that the Config type
+// does not have.
+func (cfg *Config) checkKeys() error {
+	for _, key := range cfg.lines {
+		if !knownKey(reflect.TypeOf(Config{}), key.path) {
+			return errors.Errorf("%s:%d: unknown key %s", cfg.file, key.line, strings.Join(key.path, "."))
+		}
+	}
+	return nil
+}
+
+// knownKey reports whether path leads to a value of typ: map keys are any
+// string, slice keys are indexes, struct fields are named by their yaml tag.
+func knownKey(typ reflect.Type, path []string) bool {
+	for _, key := range path {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		switch typ.Kind() {
+		case reflect.Map:
+			typ = typ.Elem()
+		case reflect.Slice:
+			if _, err := strconv.Atoi(key); err != nil {
+				return false
+			}
+			typ = typ.Elem()
+		case reflect.Struct:
+			field, ok := structField(typ, key)
+			if !ok {
+				return false
+			}
+			typ = field.Type
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func structField(typ reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if name := strings.Split(field.Tag.Get("yaml"), ",")[0]; name != "" && name == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,92 @@
+package dbtypes
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/pingcap/errors"
+)
+
+// Bit is the value of a BIT column of up to 64 bits. MySQL drivers return
+// BIT values as raw big-endian bytes, which database/sql cannot scan into an
+// integer; Bit scans them, and integers, into a uint64.
+type Bit uint64
+
+// Scan implements sql.Scanner. NULL scans as 0.
+func (b *Bit) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*b = 0
+	case []byte:
+		if len(v) > 8 {
+			return errors.Errorf("bit value of %d bytes overflows 64 bits", len(v))
+		}
+		var buf [8]byte
+		copy(buf[8-len(v):], v)
+		*b = Bit(binary.BigEndian.Uint64(buf[:]))
+	case int64:
+		*b = Bit(v)
+	case uint64:
+		*b = Bit(v)
+	default:
+		return fmt.Errorf("unsupported type %T", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer. Values that do not fit an int64 are written
+// as 8 big-endian bytes, which MySQL stores into BIT columns unchanged.
+func (b Bit) Value() (driver.Value, error) {
+	if b > math.MaxInt64 {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(b))
+		return buf[:], nil
+	}
+	return int64(b), nil
+}
+
+// NullBit is a Bit that may be NULL, in the style of the database/sql Null
+// types.
+type NullBit struct {
+	Bit   Bit
+	Valid bool // Valid is true if Bit is not NULL
+}
+
+// Scan implements sql.Scanner.
+func (n *NullBit) Scan(src interface{}) error {
+	if src == nil {
+		*n = NullBit{}
+		return nil
+	}
+	n.Valid = true
+	return n.Bit.Scan(src)
+}
+
+// Value implements driver.Valuer.
+func (n NullBit) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Bit.Value()
+}
+
+// MarshalJSON implements json.Marshaler. NULL is written as null.
+func (n NullBit) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(uint64(n.Bit))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *NullBit) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullBit{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, (*uint64)(&n.Bit))
+}
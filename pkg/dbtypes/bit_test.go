@@ -0,0 +1,70 @@
+package dbtypes
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBitScan(t *testing.T) {
+	for _, test := range []struct {
+		src  interface{}
+		want Bit
+	}{
+		{[]byte{}, 0},
+		{[]byte{0x01}, 1},
+		{[]byte{0x01, 0x02}, 0x0102},
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 1<<64 - 1},
+		{int64(5), 5},
+		{uint64(1 << 63), 1 << 63},
+		{nil, 0},
+	} {
+		b := Bit(42)
+		if err := b.Scan(test.src); err != nil {
+			t.Errorf("scan %v: %v", test.src, err)
+		} else if b != test.want {
+			t.Errorf("scan %v: got %d, want %d", test.src, b, test.want)
+		}
+	}
+
+	var b Bit
+	if err := b.Scan(make([]byte, 9)); err == nil {
+		t.Errorf("scan 9 bytes: want overflow")
+	}
+	if err := b.Scan("1"); err == nil {
+		t.Errorf("scan string: want unsupported type")
+	}
+}
+
+func TestBitValue(t *testing.T) {
+	for _, b := range []Bit{0, 1, 0x0102, 1<<63 - 1, 1 << 63, 1<<64 - 1} {
+		v, err := b.Value()
+		if err != nil {
+			t.Fatalf("value %d: %v", b, err)
+		}
+		var back Bit
+		if err := back.Scan(v); err != nil || back != b {
+			t.Errorf("round trip %d: got %d, %v", b, back, err)
+		}
+	}
+}
+
+func TestNullBit(t *testing.T) {
+	var n NullBit
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Errorf("scan NULL: got %+v, %v", n, err)
+	}
+	if data, err := json.Marshal(n); err != nil || string(data) != "null" {
+		t.Errorf("marshal NULL: got %s, %v", data, err)
+	}
+	if err := n.Scan([]byte{0x03}); err != nil || !n.Valid || n.Bit != 3 {
+		t.Errorf("scan b'11': got %+v, %v", n, err)
+	}
+	data, err := json.Marshal(n)
+	if err != nil || string(data) != "3" {
+		t.Errorf("marshal 3: got %s, %v", data, err)
+	}
+	var back NullBit
+	if err := json.Unmarshal(data, &back); err != nil || back != n {
+		t.Errorf("unmarshal %s: got %+v, %v", data, back, err)
+	}
+}
@@ -0,0 +1,302 @@
+package dbtypes
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pingcap/parser/mysql"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/stmtctx"
+	"github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// Truth is the value of an SQL condition, which is unknown when NULL is
+// involved. A row violates a CHECK constraint only when its condition is
+// false, not when it is unknown.
+type Truth int8
+
+const (
+	TruthUnknown Truth = iota
+	TruthFalse
+	TruthTrue
+)
+
+// TruthOf returns the Truth of b.
+func TruthOf(b bool) Truth {
+	if b {
+		return TruthTrue
+	}
+	return TruthFalse
+}
+
+// And returns t AND other.
+func (t Truth) And(other Truth) Truth {
+	switch {
+	case t == TruthFalse || other == TruthFalse:
+		return TruthFalse
+	case t == TruthUnknown || other == TruthUnknown:
+		return TruthUnknown
+	}
+	return TruthTrue
+}
+
+// Or returns t OR other.
+func (t Truth) Or(other Truth) Truth {
+	switch {
+	case t == TruthTrue || other == TruthTrue:
+		return TruthTrue
+	case t == TruthUnknown || other == TruthUnknown:
+		return TruthUnknown
+	}
+	return TruthFalse
+}
+
+// Not returns NOT t.
+func (t Truth) Not() Truth {
+	switch t {
+	case TruthTrue:
+		return TruthFalse
+	case TruthFalse:
+		return TruthTrue
+	}
+	return TruthUnknown
+}
+
+// IsFalse reports whether t is false, that is whether a CHECK constraint is violated.
+func (t Truth) IsFalse() bool {
+	return t == TruthFalse
+}
+
+// CheckError is the error of a row violating a CHECK constraint.
+type CheckError struct {
+	Constraint string
+	Expr       string // the CHECK expression, as SQL text
+}
+
+func (e *CheckError) Error() string {
+	return fmt.Sprintf("check constraint '%s' is violated: %s", e.Constraint, e.Expr)
+}
+
+// checkStmtCtx converts operands as MySQL does when evaluating conditions:
+// strings that are not numbers compare as their numeric prefix.
+func checkStmtCtx() *stmtctx.StatementContext {
+	return &stmtctx.StatementContext{TimeZone: time.UTC, IgnoreTruncate: true}
+}
+
+// CheckValue is an operand of a CHECK constraint: a field or a constant, held
+// as a TiDB datum so that it converts and collates as in MySQL.
+type CheckValue struct {
+	d types.Datum
+}
+
+// CheckValueOf returns the operand of v, a field of a generated struct or a
+// constant. nil pointers, invalid Null values and values whose driver.Valuer
+// fails are NULL. Strings are compared with collation, or with the collation
+// of the other operand when empty.
+func CheckValueOf(v interface{}, collation string) CheckValue {
+	var value CheckValue
+	if err := setDatum(&value.d, v, collation); err != nil {
+		value.d.SetNull()
+	}
+	return value
+}
+
+// setDatum sets d to v. It fails when v is a driver.Valuer that fails, as
+// generated ENUM types do for values that are not elements.
+func setDatum(d *types.Datum, v interface{}, collation string) error {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		d.SetNull()
+		return nil
+	}
+	switch v := v.(type) {
+	case nil:
+		d.SetNull()
+	case Decimal:
+		dec := v.d
+		d.SetMysqlDecimal(&dec)
+	case NullDecimal:
+		return setNullDatum(d, v.Decimal, v.Valid, collation)
+	case MySQLTime:
+		d.SetMysqlTime(v.t)
+	case NullMySQLTime:
+		return setNullDatum(d, v.MySQLTime, v.Valid, collation)
+	case Duration:
+		d.SetMysqlDuration(v.d)
+	case NullDuration:
+		return setNullDatum(d, v.Duration, v.Valid, collation)
+	case JSON:
+		d.SetMysqlJSON(v.binary())
+	case time.Time:
+		d.SetMysqlTime(types.NewTime(types.FromGoTime(v), mysql.TypeDatetime, types.MaxFsp))
+	case []byte:
+		d.SetBytes(v)
+	case string:
+		d.SetString(v, collation)
+	case bool:
+		if v {
+			d.SetInt64(1)
+		} else {
+			d.SetInt64(0)
+		}
+	case driver.Valuer:
+		value, err := v.Value()
+		if err != nil {
+			return err
+		}
+		return setDatum(d, value, collation)
+	default:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Ptr:
+			return setDatum(d, rv.Elem().Interface(), collation)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			d.SetInt64(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			d.SetUint64(rv.Uint())
+		case reflect.Float32, reflect.Float64:
+			d.SetFloat64(rv.Float())
+		case reflect.String:
+			d.SetString(rv.String(), collation)
+		case reflect.Bool:
+			return setDatum(d, rv.Bool(), collation)
+		default:
+			d.SetString(fmt.Sprint(v), collation)
+		}
+	}
+	return nil
+}
+
+func setNullDatum(d *types.Datum, v interface{}, valid bool, collation string) error {
+	if !valid {
+		d.SetNull()
+		return nil
+	}
+	return setDatum(d, v, collation)
+}
+
+// IsNull returns the Truth of v IS NULL.
+func (v CheckValue) IsNull() Truth {
+	return TruthOf(v.d.IsNull())
+}
+
+// Truth returns v as a condition: unknown when NULL, false when 0.
+func (v CheckValue) Truth() Truth {
+	if v.d.IsNull() {
+		return TruthUnknown
+	}
+	b, err := v.d.ToBool(checkStmtCtx())
+	if err != nil {
+		return TruthUnknown
+	}
+	return TruthOf(b != 0)
+}
+
+// withCollation returns v and other, a string without collation taking the
+// collation of the other operand.
+func withCollation(v, other CheckValue) (CheckValue, CheckValue) {
+	if v.d.Kind() == types.KindString && v.d.Collation() == "" {
+		v.d.SetString(v.d.GetString(), other.collation())
+	}
+	if other.d.Kind() == types.KindString && other.d.Collation() == "" {
+		other.d.SetString(other.d.GetString(), v.collation())
+	}
+	return v, other
+}
+
+// collation returns the collation of a string v, or the default collation.
+func (v CheckValue) collation() string {
+	if collation := v.d.Collation(); collation != "" {
+		if _, ok := collators[collation]; ok {
+			return collation
+		}
+	}
+	return mysql.DefaultCollationName
+}
+
+// compare returns -1, 0 or +1 as v is below, equal to or above other, and
+// false when either is NULL or they do not compare.
+func (v CheckValue) compare(other CheckValue) (int, bool) {
+	if v.d.IsNull() || other.d.IsNull() {
+		return 0, false
+	}
+	v, other = withCollation(v, other)
+	if v.d.Kind() == types.KindString && other.d.Kind() == types.KindString {
+		// Datum.CompareDatum only applies collations once they are enabled
+		// process-wide.
+		return collator(v.collation()).Compare(v.d.GetString(), other.d.GetString()), true
+	}
+	cmp, err := v.d.CompareDatum(checkStmtCtx(), &other.d)
+	if err != nil {
+		return 0, false
+	}
+	return cmp, true
+}
+
+// Compare returns the Truth of v op other, where op is one of =, <=>, <>, !=,
+// <, <=, > and >=.
+func (v CheckValue) Compare(op string, other CheckValue) Truth {
+	if op == "<=>" {
+		if v.d.IsNull() || other.d.IsNull() {
+			return TruthOf(v.d.IsNull() && other.d.IsNull())
+		}
+		op = "="
+	}
+	cmp, ok := v.compare(other)
+	if !ok {
+		return TruthUnknown
+	}
+	switch op {
+	case "=":
+		return TruthOf(cmp == 0)
+	case "<>", "!=":
+		return TruthOf(cmp != 0)
+	case "<":
+		return TruthOf(cmp < 0)
+	case "<=":
+		return TruthOf(cmp <= 0)
+	case ">":
+		return TruthOf(cmp > 0)
+	case ">=":
+		return TruthOf(cmp >= 0)
+	}
+	panic(fmt.Sprintf("dbtypes: unknown comparison operator %q", op))
+}
+
+// In returns the Truth of v IN (list...).
+func (v CheckValue) In(list ...CheckValue) Truth {
+	result := TruthFalse
+	for _, item := range list {
+		result = result.Or(v.Compare("=", item))
+		if result == TruthTrue {
+			break
+		}
+	}
+	return result
+}
+
+// Between returns the Truth of v BETWEEN low AND high.
+func (v CheckValue) Between(low, high CheckValue) Truth {
+	return v.Compare(">=", low).And(v.Compare("<=", high))
+}
+
+// Like returns the Truth of v LIKE pattern ESCAPE escape, matched with the
+// collation of v.
+func (v CheckValue) Like(pattern CheckValue, escape byte) Truth {
+	if v.d.IsNull() || pattern.d.IsNull() {
+		return TruthUnknown
+	}
+	v, pattern = withCollation(v, pattern)
+	text, err := v.d.ToString()
+	if err != nil {
+		return TruthUnknown
+	}
+	patternText, err := pattern.d.ToString()
+	if err != nil {
+		return TruthUnknown
+	}
+	matcher := collator(v.collation()).Pattern()
+	matcher.Compile(patternText, escape)
+	return TruthOf(matcher.DoMatch(text))
+}
@@ -0,0 +1,87 @@
+package dbtypes
+
+import (
+	"strings"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/util/collate"
+	"github.com/Sterrenhemel/ddl2struct/pkg/util/stringutil"
+)
+
+// collators are the collators of the collations MySQL compares strings
+// with. The vendored collate package returns them only once the new
+// collations are enabled process-wide, which dbtypes leaves to the program.
+var collators = map[string]collate.Collator{
+	"binary":             &binCollator{},
+	"ascii_bin":          &binPaddingCollator{},
+	"latin1_bin":         &binPaddingCollator{},
+	"utf8_bin":           &binPaddingCollator{},
+	"utf8mb4_bin":        &binPaddingCollator{},
+	"utf8_general_ci":    &generalCICollator{},
+	"utf8mb4_general_ci": &generalCICollator{},
+}
+
+// collator returns the collator of collation. Collations without one
+// compare as utf8mb4_bin.
+func collator(collation string) collate.Collator {
+	if ctor, ok := collators[collation]; ok {
+		return ctor
+	}
+	return collators["utf8mb4_bin"]
+}
+
+// binCollator compares bytes, trailing spaces included.
+type binCollator struct{}
+
+// Compare implements collate.Collator.
+func (bc *binCollator) Compare(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+// Key implements collate.Collator.
+func (bc *binCollator) Key(str string) []byte {
+	return []byte(str)
+}
+
+// Pattern implements collate.Collator.
+func (bc *binCollator) Pattern() collate.WildcardPattern {
+	return &binPattern{}
+}
+
+// binPaddingCollator compares bytes, ignoring trailing spaces as PAD SPACE
+// collations do.
+type binPaddingCollator struct{}
+
+// Compare implements collate.Collator.
+func (bpc *binPaddingCollator) Compare(a, b string) int {
+	return strings.Compare(truncateTailingSpace(a), truncateTailingSpace(b))
+}
+
+// Key implements collate.Collator.
+func (bpc *binPaddingCollator) Key(str string) []byte {
+	return []byte(truncateTailingSpace(str))
+}
+
+// Pattern implements collate.Collator. Trailing spaces are significant in
+// patterns.
+func (bpc *binPaddingCollator) Pattern() collate.WildcardPattern {
+	return &binPattern{}
+}
+
+type binPattern struct {
+	patChars []byte
+	patTypes []byte
+}
+
+// Compile implements collate.WildcardPattern.
+func (p *binPattern) Compile(patternStr string, escape byte) {
+	p.patChars, p.patTypes = stringutil.CompilePattern(patternStr, escape)
+}
+
+// DoMatch implements collate.WildcardPattern.
+func (p *binPattern) DoMatch(str string) bool {
+	return stringutil.DoMatch(str, p.patChars, p.patTypes)
+}
+
+func truncateTailingSpace(str string) string {
+	return strings.TrimRight(str, " ")
+}
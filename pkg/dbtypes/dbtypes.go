@@ -0,0 +1,19 @@
+// Package dbtypes holds the runtime types and helpers the generated structs
+// refer to. They rely on the vendored TiDB types so that values behave as they
+// do in MySQL.
+package dbtypes
+
+import "fmt"
+
+// scanText returns the text of a value read from a database driver.
+func scanText(src interface{}) (string, bool, error) {
+	switch v := src.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return v, true, nil
+	case []byte:
+		return string(v), true, nil
+	}
+	return "", false, fmt.Errorf("unsupported type %T", src)
+}
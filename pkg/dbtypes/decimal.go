@@ -0,0 +1,251 @@
+package dbtypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pingcap/errors"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// divFracIncr is the number of fractional digits Div adds to the dividend's,
+// as div_precision_increment does in MySQL.
+const divFracIncr = 4
+
+// Decimal is the exact value of a DECIMAL column, held as a MySQL decimal so
+// that no digit is lost on the way to and from the database. The zero value
+// is 0.
+type Decimal struct {
+	d types.MyDecimal
+}
+
+// ParseDecimal parses the text of a decimal number, e.g. "-12.30" or "1e3".
+func ParseDecimal(text string) (Decimal, error) {
+	var d Decimal
+	if err := d.d.FromString([]byte(text)); err != nil {
+		return Decimal{}, errors.Annotatef(err, "parse decimal %q", text)
+	}
+	return d, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics on malformed text. It is
+// meant for constants, such as the column defaults of generated constructors.
+func MustParseDecimal(text string) Decimal {
+	d, err := ParseDecimal(text)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromInt returns the decimal of i.
+func DecimalFromInt(i int64) Decimal {
+	return Decimal{d: *types.NewDecFromInt(i)}
+}
+
+// DecimalFromFloat returns the shortest decimal that rounds to f.
+func DecimalFromFloat(f float64) (Decimal, error) {
+	var d Decimal
+	if err := d.d.FromFloat64(f); err != nil {
+		return Decimal{}, err
+	}
+	return d, nil
+}
+
+// String returns d as exact decimal text, with the digits of its scale.
+func (d Decimal) String() string {
+	return d.d.String()
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := d.d.ToFloat64()
+	return f
+}
+
+// Int64 returns the integer part of d, or an error if it overflows int64.
+func (d Decimal) Int64() (int64, error) {
+	var truncated types.MyDecimal
+	if err := d.d.Round(&truncated, 0, types.ModeTruncate); err != nil {
+		return 0, err
+	}
+	return truncated.ToInt()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.d.IsZero()
+}
+
+// IsNegative reports whether d is below 0.
+func (d Decimal) IsNegative() bool {
+	return d.d.IsNegative() && !d.d.IsZero()
+}
+
+// Cmp returns -1, 0 or +1 as d is below, equal to or above other.
+func (d Decimal) Cmp(other Decimal) int {
+	return d.d.Compare(&other.d)
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{d: *types.DecimalNeg(&d.d)}
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	var sum Decimal
+	err := types.DecimalAdd(&d.d, &other.d, &sum.d)
+	return sum, err
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) (Decimal, error) {
+	var diff Decimal
+	err := types.DecimalSub(&d.d, &other.d, &diff.d)
+	return diff, err
+}
+
+// Mul returns d * other.
+func (d Decimal) Mul(other Decimal) (Decimal, error) {
+	var product Decimal
+	err := types.DecimalMul(&d.d, &other.d, &product.d)
+	return product, err
+}
+
+// Div returns d / other with 4 more fractional digits than d, as MySQL does.
+// Dividing by 0 is an error.
+func (d Decimal) Div(other Decimal) (Decimal, error) {
+	var quotient Decimal
+	err := types.DecimalDiv(&d.d, &other.d, &quotient.d, divFracIncr)
+	return quotient, err
+}
+
+// Round rounds d half away from zero to scale fractional digits.
+func (d Decimal) Round(scale int) Decimal {
+	var rounded Decimal
+	_ = d.d.Round(&rounded.d, scale, types.ModeHalfEven)
+	return rounded
+}
+
+// PrecisionAndScale returns the number of digits of d and how many of them
+// are fractional.
+func (d Decimal) PrecisionAndScale() (precision, scale int) {
+	return d.d.PrecisionAndFrac()
+}
+
+// Fit returns d as stored in a DECIMAL(precision, scale) column: rounded to
+// scale, or an error if its integer part has more than precision-scale digits.
+func (d Decimal) Fit(precision, scale int) (Decimal, error) {
+	rounded := d.Round(scale)
+	p, s := rounded.PrecisionAndScale()
+	if p-s > precision-scale {
+		return d, errors.Errorf("decimal %s out of range for decimal(%d,%d)", d, precision, scale)
+	}
+	return rounded, nil
+}
+
+// Scan implements sql.Scanner. NULL scans as 0.
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*d = DecimalFromInt(v)
+		return nil
+	case float64:
+		f, err := DecimalFromFloat(v)
+		if err != nil {
+			return err
+		}
+		*d = f
+		return nil
+	}
+	text, ok, err := scanText(src)
+	if err != nil {
+		return errors.Annotate(err, "scan decimal")
+	}
+	if !ok {
+		*d = Decimal{}
+		return nil
+	}
+	parsed, err := ParseDecimal(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value implements driver.Valuer. Decimals are sent as text, which MySQL
+// converts without loss.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// MarshalJSON implements json.Marshaler. d is written as a string, so that
+// readers parsing numbers as float64 do not lose digits.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both strings and numbers are
+// accepted; null leaves d unchanged, as encoding/json does for other values.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	parsed, err := ParseDecimal(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// NullDecimal is a Decimal that may be NULL, in the style of the database/sql
+// Null types.
+type NullDecimal struct {
+	Decimal Decimal
+	Valid   bool // Valid is true if Decimal is not NULL
+}
+
+// Scan implements sql.Scanner.
+func (n *NullDecimal) Scan(src interface{}) error {
+	if src == nil {
+		*n = NullDecimal{}
+		return nil
+	}
+	n.Valid = true
+	return n.Decimal.Scan(src)
+}
+
+// Value implements driver.Valuer.
+func (n NullDecimal) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Decimal.Value()
+}
+
+// MarshalJSON implements json.Marshaler. NULL is written as null.
+func (n NullDecimal) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Decimal.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *NullDecimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullDecimal{}
+		return nil
+	}
+	n.Valid = true
+	return n.Decimal.UnmarshalJSON(data)
+}
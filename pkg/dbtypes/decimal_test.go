@@ -0,0 +1,84 @@
+package dbtypes
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecimalUnmarshalJSON(t *testing.T) {
+	for _, test := range []struct {
+		data string
+		want string
+	}{
+		{`{"d":"12.30"}`, "12.30"},
+		{`{"d":12.30}`, "12.30"},
+		{`{"d":-1e3}`, "-1000"},
+		{`{"d":null}`, "7"},
+		{`{}`, "7"},
+	} {
+		v := struct {
+			D Decimal `json:"d"`
+		}{D: MustParseDecimal("7")}
+		if err := json.Unmarshal([]byte(test.data), &v); err != nil {
+			t.Errorf("unmarshal %s: %v", test.data, err)
+			continue
+		}
+		if got := v.D.String(); got != test.want {
+			t.Errorf("unmarshal %s: got %s, want %s", test.data, got, test.want)
+		}
+	}
+	var d Decimal
+	if err := json.Unmarshal([]byte(`"abc"`), &d); err == nil {
+		t.Errorf("unmarshal abc: got %s, want an error", d)
+	}
+}
+
+func TestNullDecimalUnmarshalJSON(t *testing.T) {
+	n := NullDecimal{Decimal: MustParseDecimal("1"), Valid: true}
+	if err := json.Unmarshal([]byte("null"), &n); err != nil {
+		t.Fatal(err)
+	}
+	if n.Valid {
+		t.Errorf("null: got %s, want NULL", n.Decimal)
+	}
+}
+
+func TestDecimalPrecision(t *testing.T) {
+	// Neither a float64 nor a JSON number read as one keeps these digits.
+	const text = "12345678901234567890.123456789012345678"
+	d := MustParseDecimal(text)
+	if got := d.String(); got != text {
+		t.Errorf("parse: got %s, want %s", got, text)
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"`+text+`"` {
+		t.Errorf("marshal: got %s, want the quoted text", data)
+	}
+	var back Decimal
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Cmp(d) != 0 {
+		t.Errorf("round trip: got %s, want %s", back, text)
+	}
+	if err := json.Unmarshal([]byte(text), &back); err != nil || back.String() != text {
+		t.Errorf("unquoted number: got %s, %v, want %s", back, err, text)
+	}
+	if f, err := DecimalFromFloat(d.Float64()); err != nil || f.Cmp(d) == 0 {
+		t.Errorf("float64: got %s, %v, want digits lost", f, err)
+	}
+
+	sum, err := MustParseDecimal("0.1").Add(MustParseDecimal("0.2"))
+	if err != nil || sum.String() != "0.3" {
+		t.Errorf("0.1 + 0.2: got %s, %v, want 0.3", sum, err)
+	}
+	if fit, err := MustParseDecimal("123.456").Fit(5, 2); err != nil || fit.String() != "123.46" {
+		t.Errorf("fit 123.456 in DECIMAL(5,2): got %s, %v, want 123.46", fit, err)
+	}
+	if _, err := MustParseDecimal("123.456").Fit(4, 2); err == nil {
+		t.Errorf("fit 123.456 in DECIMAL(4,2): want an error")
+	}
+}
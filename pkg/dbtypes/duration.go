@@ -0,0 +1,242 @@
+package dbtypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/stmtctx"
+	"github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// Duration is the value of a TIME column: a signed time of day or elapsed
+// time between '-838:59:59' and '838:59:59', with the fractional seconds
+// precision of the column. The zero value is '00:00:00'.
+type Duration struct {
+	d types.Duration
+}
+
+// strictStmtCtx rejects values MySQL rejects in strict SQL mode.
+func strictStmtCtx() *stmtctx.StatementContext {
+	return &stmtctx.StatementContext{TimeZone: time.UTC}
+}
+
+// checkDuration returns d, or an error if it is out of the range of TIME.
+func checkDuration(d types.Duration, text string) (Duration, error) {
+	if _, err := types.TruncateOverflowMySQLTime(d.Duration); err != nil {
+		return Duration{}, errors.Errorf("time value %s out of range", text)
+	}
+	return Duration{d: d}, nil
+}
+
+// ParseDuration parses MySQL TIME text, e.g. "12:34:56", "-838:59:59",
+// "1 02:03", "123456" or a datetime whose time part is taken. The fractional
+// seconds precision is that of the text.
+func ParseDuration(text string) (Duration, error) {
+	fsp := types.DefaultFsp
+	if i := strings.LastIndexByte(text, '.'); i >= 0 {
+		fsp = types.GetFsp(strings.TrimSpace(text[i:]))
+	}
+	d, t, isDuration, err := types.StrToDuration(strictStmtCtx(), text, fsp)
+	if err != nil {
+		return Duration{}, errors.Annotatef(err, "parse time %q", text)
+	}
+	if !isDuration {
+		if d, err = t.ConvertToDuration(); err != nil {
+			return Duration{}, errors.Annotatef(err, "parse time %q", text)
+		}
+	}
+	return checkDuration(d, text)
+}
+
+// MustParseDuration is like ParseDuration but panics on malformed text. It is
+// meant for constants, such as the column defaults of generated constructors.
+func MustParseDuration(text string) Duration {
+	d, err := ParseDuration(text)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DurationOf returns d as a TIME rounded to fsp fractional digits, or an error
+// if it is out of range.
+func DurationOf(d time.Duration, fsp int) (Duration, error) {
+	checked, err := types.CheckFsp(fsp)
+	if err != nil {
+		return Duration{}, err
+	}
+	rounded, err := types.Duration{Duration: d, Fsp: types.MaxFsp}.RoundFrac(checked)
+	if err != nil {
+		return Duration{}, err
+	}
+	return checkDuration(rounded, d.String())
+}
+
+// DurationFromNumber returns the TIME of a number in the HHMMSS format, as
+// MySQL reads numbers stored into TIME columns.
+func DurationFromNumber(number int64) (Duration, error) {
+	d, err := types.NumberToDuration(number, types.DefaultFsp)
+	if err != nil {
+		return Duration{}, err
+	}
+	return Duration{d: d}, nil
+}
+
+// String returns d as MySQL writes it, e.g. "-01:02:03.450".
+func (d Duration) String() string {
+	return d.d.String()
+}
+
+// GoDuration returns d as a time.Duration.
+func (d Duration) GoDuration() time.Duration {
+	return d.d.Duration
+}
+
+// Fsp returns the number of fractional seconds digits of d.
+func (d Duration) Fsp() int {
+	return int(d.d.Fsp)
+}
+
+// IsNegative reports whether d is below 0.
+func (d Duration) IsNegative() bool {
+	return d.d.Duration < 0
+}
+
+// Clock returns the absolute hours, minutes, seconds and microseconds of d.
+// Hours go up to 838.
+func (d Duration) Clock() (hour, minute, second, microsecond int) {
+	return d.d.Hour(), d.d.Minute(), d.d.Second(), d.d.MicroSecond()
+}
+
+// Add returns d + other, or an error if the sum is out of range.
+func (d Duration) Add(other Duration) (Duration, error) {
+	sum, err := d.d.Add(other.d)
+	if err != nil {
+		return Duration{}, err
+	}
+	return checkDuration(sum, sum.Duration.String())
+}
+
+// Sub returns d - other, or an error if the difference is out of range.
+func (d Duration) Sub(other Duration) (Duration, error) {
+	diff, err := d.d.Sub(other.d)
+	if err != nil {
+		return Duration{}, err
+	}
+	return checkDuration(diff, diff.Duration.String())
+}
+
+// Round rounds d to fsp fractional seconds digits.
+func (d Duration) Round(fsp int) (Duration, error) {
+	rounded, err := d.d.RoundFrac(int8(fsp))
+	if err != nil {
+		return d, err
+	}
+	return checkDuration(rounded, rounded.Duration.String())
+}
+
+// Compare returns -1, 0 or +1 as d is below, equal to or above other.
+func (d Duration) Compare(other Duration) int {
+	return d.d.Compare(other.d)
+}
+
+// Scan implements sql.Scanner. NULL scans as '00:00:00'.
+func (d *Duration) Scan(src interface{}) error {
+	if n, ok := src.(int64); ok {
+		parsed, err := DurationFromNumber(n)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	}
+	text, ok, err := scanText(src)
+	if err != nil {
+		return errors.Annotate(err, "scan time")
+	}
+	if !ok {
+		*d = Duration{}
+		return nil
+	}
+	parsed, err := ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (d Duration) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// MarshalJSON implements json.Marshaler, writing d as MySQL text.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, reading MySQL text; null leaves
+// d unchanged, as encoding/json does for other values.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	parsed, err := ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// NullDuration is a Duration that may be NULL, in the style of the
+// database/sql Null types.
+type NullDuration struct {
+	Duration Duration
+	Valid    bool // Valid is true if Duration is not NULL
+}
+
+// Scan implements sql.Scanner.
+func (n *NullDuration) Scan(src interface{}) error {
+	if src == nil {
+		*n = NullDuration{}
+		return nil
+	}
+	n.Valid = true
+	return n.Duration.Scan(src)
+}
+
+// Value implements driver.Valuer.
+func (n NullDuration) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Duration.Value()
+}
+
+// MarshalJSON implements json.Marshaler. NULL is written as null.
+func (n NullDuration) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Duration.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *NullDuration) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullDuration{}
+		return nil
+	}
+	n.Valid = true
+	return n.Duration.UnmarshalJSON(data)
+}
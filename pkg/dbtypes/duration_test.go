@@ -0,0 +1,73 @@
+package dbtypes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDurationRange(t *testing.T) {
+	for _, test := range []struct {
+		text string
+		want string // empty when out of range
+	}{
+		{"838:59:59", "838:59:59"},
+		{"-838:59:59", "-838:59:59"},
+		{"838:59:59.000000", "838:59:59.000000"},
+		{"12:34:56", "12:34:56"},
+		{"1 02:03", "26:03:00"},
+		{"123456", "12:34:56"},
+		{"839:00:00", ""},
+		{"-839:00:00", ""},
+		{"838:59:59.5", ""},
+		{"-838:59:59.5", ""},
+	} {
+		d, err := ParseDuration(test.text)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("parse %s: got %s, want out of range", test.text, d)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parse %s: %v", test.text, err)
+		} else if got := d.String(); got != test.want {
+			t.Errorf("parse %s: got %s, want %s", test.text, got, test.want)
+		}
+	}
+
+	limit := 838*time.Hour + 59*time.Minute + 59*time.Second
+	if _, err := DurationOf(-limit, 0); err != nil {
+		t.Errorf("-838:59:59: %v", err)
+	}
+	if _, err := DurationOf(limit+time.Second, 0); err == nil {
+		t.Errorf("839:00:00: want out of range")
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	d := MustParseDuration("-01:02:03.450")
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Duration
+	if err := json.Unmarshal(data, &back); err != nil || back.Compare(d) != 0 {
+		t.Errorf("round trip %s: got %s, %v", data, back, err)
+	}
+
+	v := struct {
+		D Duration `json:"d"`
+	}{D: d}
+	if err := json.Unmarshal([]byte(`{"d":null}`), &v); err != nil {
+		t.Fatalf("unmarshal null: %v", err)
+	}
+	if v.D.Compare(d) != 0 {
+		t.Errorf("unmarshal null: got %s, want the value unchanged", v.D)
+	}
+
+	n := NullDuration{Duration: d, Valid: true}
+	if err := json.Unmarshal([]byte("null"), &n); err != nil || n.Valid {
+		t.Errorf("unmarshal null into NullDuration: got %+v, %v, want NULL", n, err)
+	}
+}
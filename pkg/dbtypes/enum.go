@@ -0,0 +1,65 @@
+package dbtypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pingcap/errors"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// ParseEnum returns the element of an ENUM that name stands for, matched with
+// collation as MySQL does. A number is taken as the 1-based ordinal.
+func ParseEnum(elems []string, collation string, name string) (string, error) {
+	ctor := collator(collation)
+	for _, elem := range elems {
+		if ctor.Compare(elem, name) == 0 {
+			return elem, nil
+		}
+	}
+	if num, err := strconv.ParseUint(name, 0, 64); err == nil {
+		enum, err := types.ParseEnumValue(elems, num)
+		return enum.Name, err
+	}
+	return "", errors.Errorf("item %s is not in enum %v", name, elems)
+}
+
+// IsValidEnum reports whether name is an element of an ENUM.
+func IsValidEnum(elems []string, collation string, name string) bool {
+	_, err := ParseEnum(elems, collation, name)
+	return err == nil
+}
+
+// ScanEnum implements sql.Scanner for generated ENUM types. NULL scans as "".
+func ScanEnum(elems []string, collation string, src interface{}) (string, error) {
+	if n, ok := src.(int64); ok {
+		enum, err := types.ParseEnumValue(elems, uint64(n))
+		return enum.Name, err
+	}
+	text, ok, err := scanText(src)
+	if err != nil || !ok {
+		return "", errors.Annotate(err, "scan enum")
+	}
+	return ParseEnum(elems, collation, text)
+}
+
+// EnumValue implements driver.Valuer for generated ENUM types.
+func EnumValue(elems []string, collation string, name string) (driver.Value, error) {
+	return ParseEnum(elems, collation, name)
+}
+
+// MarshalEnum implements json.Marshaler for generated ENUM types.
+func MarshalEnum(name string) ([]byte, error) {
+	return json.Marshal(name)
+}
+
+// UnmarshalEnum implements json.Unmarshaler for generated ENUM types.
+func UnmarshalEnum(elems []string, collation string, data []byte) (string, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return "", err
+	}
+	return ParseEnum(elems, collation, name)
+}
@@ -0,0 +1,61 @@
+package dbtypes
+
+import (
+	"testing"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/util/collate"
+)
+
+func TestEnumCollation(t *testing.T) {
+	elems := []string{"Small", "Large"}
+	for _, test := range []struct {
+		collation string
+		name      string
+		want      string // empty when not an element
+	}{
+		{"utf8mb4_general_ci", "small", "Small"},
+		{"utf8mb4_general_ci", "LARGE ", "Large"},
+		{"utf8mb4_bin", "small", ""},
+		{"utf8mb4_bin", "Small", "Small"},
+		{"binary", "Small ", ""},
+		{"utf8mb4_general_ci", "2", "Large"},
+	} {
+		got, err := ParseEnum(elems, test.collation, test.name)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("%s %q: got %s, want an error", test.collation, test.name, got)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("%s %q: got %s, %v, want %s", test.collation, test.name, got, err, test.want)
+		}
+	}
+	// The collations apply without switching the global setting.
+	if collate.NewCollationEnabled() {
+		t.Errorf("importing dbtypes enabled the new collations")
+	}
+}
+
+func TestSetCollation(t *testing.T) {
+	elems := []string{"Red", "Green", "Blue"}
+	for _, test := range []struct {
+		collation string
+		name      string
+		want      uint64
+		ok        bool
+	}{
+		{"utf8mb4_general_ci", "red,BLUE", 5, true},
+		{"utf8mb4_general_ci", "green ", 2, true},
+		{"utf8mb4_general_ci", "", 0, true},
+		{"utf8mb4_general_ci", "3", 3, true},
+		{"utf8mb4_bin", "red", 0, false},
+		{"utf8mb4_bin", "Red,Blue", 5, true},
+		{"utf8mb4_general_ci", "red,pink", 0, false},
+	} {
+		got, err := ParseSet(elems, test.collation, test.name)
+		if (err == nil) != test.ok || got != test.want {
+			t.Errorf("%s %q: got %d, %v, want %d", test.collation, test.name, got, err, test.want)
+		}
+	}
+}
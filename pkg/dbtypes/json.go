@@ -0,0 +1,205 @@
+package dbtypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+
+	tidbjson "github.com/Sterrenhemel/ddl2struct/pkg/types/json"
+)
+
+// JSON is the value of a JSON column, held as MySQL binary JSON so that paths,
+// comparisons and merges behave as the JSON functions of MySQL. The zero
+// value is the JSON null literal. JSON values are immutable: Set, Remove and
+// Merge return a new document.
+type JSON struct {
+	bj tidbjson.BinaryJSON
+}
+
+// ParseJSON parses a JSON document.
+func ParseJSON(text string) (JSON, error) {
+	bj, err := tidbjson.ParseBinaryFromString(text)
+	if err != nil {
+		return JSON{}, err
+	}
+	return JSON{bj: bj}, nil
+}
+
+// JSONOf returns the JSON document of v, as encoded by encoding/json. A JSON
+// value is returned unchanged.
+func JSONOf(v interface{}) (JSON, error) {
+	switch v := v.(type) {
+	case JSON:
+		return v, nil
+	case *JSON:
+		return *v, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return JSON{}, err
+	}
+	return ParseJSON(string(data))
+}
+
+func (j JSON) binary() tidbjson.BinaryJSON {
+	if j.bj.Value == nil {
+		return tidbjson.CreateBinary(nil)
+	}
+	return j.bj
+}
+
+// IsNull reports whether j is the JSON null literal.
+func (j JSON) IsNull() bool {
+	bj := j.binary()
+	return bj.TypeCode == tidbjson.TypeCodeLiteral && bj.Value[0] == tidbjson.LiteralNil
+}
+
+// Type returns the JSON type of j as JSON_TYPE does, e.g. "OBJECT" or "INTEGER".
+func (j JSON) Type() string {
+	return j.binary().Type()
+}
+
+// String returns j as JSON text.
+func (j JSON) String() string {
+	return j.binary().String()
+}
+
+// Unquote returns j as JSON_UNQUOTE does: strings without quotes, other
+// values as JSON text.
+func (j JSON) Unquote() (string, error) {
+	return j.binary().Unquote()
+}
+
+// Unmarshal decodes j into v with encoding/json.
+func (j JSON) Unmarshal(v interface{}) error {
+	return json.Unmarshal([]byte(j.String()), v)
+}
+
+// Get returns the value at path, e.g. "$.a.b" or "$[0]", as JSON_EXTRACT does.
+// A path with wildcards returns the array of the matches.
+func (j JSON) Get(path string) (JSON, bool, error) {
+	pathExpr, err := tidbjson.ParseJSONPathExpr(path)
+	if err != nil {
+		return JSON{}, false, err
+	}
+	bj, found := j.binary().Extract([]tidbjson.PathExpression{pathExpr})
+	if !found {
+		return JSON{}, false, nil
+	}
+	return JSON{bj: bj}, true, nil
+}
+
+// GetUnquoted returns the value at path as the ->> operator does: strings
+// without quotes, other values as JSON text.
+func (j JSON) GetUnquoted(path string) (string, bool, error) {
+	v, found, err := j.Get(path)
+	if err != nil || !found {
+		return "", found, err
+	}
+	text, err := v.Unquote()
+	return text, true, err
+}
+
+// Set stores value at path as JSON_SET does, replacing an existing value or
+// adding a missing one. value is converted with JSONOf.
+func (j JSON) Set(path string, value interface{}) (JSON, error) {
+	return j.modify(path, value, tidbjson.ModifySet)
+}
+
+// Insert stores value at path as JSON_INSERT does, only if path is missing.
+func (j JSON) Insert(path string, value interface{}) (JSON, error) {
+	return j.modify(path, value, tidbjson.ModifyInsert)
+}
+
+// Replace stores value at path as JSON_REPLACE does, only if path exists.
+func (j JSON) Replace(path string, value interface{}) (JSON, error) {
+	return j.modify(path, value, tidbjson.ModifyReplace)
+}
+
+func (j JSON) modify(path string, value interface{}, mt tidbjson.ModifyType) (JSON, error) {
+	pathExpr, err := tidbjson.ParseJSONPathExpr(path)
+	if err != nil {
+		return j, err
+	}
+	v, err := JSONOf(value)
+	if err != nil {
+		return j, err
+	}
+	bj, err := j.binary().Modify([]tidbjson.PathExpression{pathExpr}, []tidbjson.BinaryJSON{v.binary()}, mt)
+	if err != nil {
+		return j, err
+	}
+	return JSON{bj: bj}, nil
+}
+
+// Remove deletes the value at path as JSON_REMOVE does.
+func (j JSON) Remove(path string) (JSON, error) {
+	pathExpr, err := tidbjson.ParseJSONPathExpr(path)
+	if err != nil {
+		return j, err
+	}
+	bj, err := j.binary().Remove([]tidbjson.PathExpression{pathExpr})
+	if err != nil {
+		return j, err
+	}
+	return JSON{bj: bj}, nil
+}
+
+// Contains reports whether candidate is contained in j, as JSON_CONTAINS does.
+func (j JSON) Contains(candidate JSON) bool {
+	return tidbjson.ContainsBinary(j.binary(), candidate.binary())
+}
+
+// Merge merges j with others as JSON_MERGE_PRESERVE does.
+func (j JSON) Merge(others ...JSON) JSON {
+	bjs := make([]tidbjson.BinaryJSON, 0, len(others)+1)
+	bjs = append(bjs, j.binary())
+	for _, other := range others {
+		bjs = append(bjs, other.binary())
+	}
+	return JSON{bj: tidbjson.MergeBinary(bjs)}
+}
+
+// Equal reports whether j and other are the same document, as = compares them.
+func (j JSON) Equal(other JSON) bool {
+	return tidbjson.CompareBinary(j.binary(), other.binary()) == 0
+}
+
+// Scan implements sql.Scanner. NULL scans as the JSON null literal.
+func (j *JSON) Scan(src interface{}) error {
+	text, ok, err := scanText(src)
+	if err != nil {
+		return errors.Annotate(err, "scan json")
+	}
+	if !ok {
+		*j = JSON{}
+		return nil
+	}
+	v, err := ParseJSON(text)
+	if err != nil {
+		return err
+	}
+	*j = v
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (j JSON) Value() (driver.Value, error) {
+	return j.String(), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j JSON) MarshalJSON() ([]byte, error) {
+	return j.binary().MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	var bj tidbjson.BinaryJSON
+	if err := bj.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	j.bj = bj
+	return nil
+}
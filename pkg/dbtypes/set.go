@@ -0,0 +1,126 @@
+package dbtypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// ParseSet returns the bits of a SET value written as comma separated
+// elements, matched with collation as MySQL does. A number is taken as the
+// bits themselves.
+func ParseSet(elems []string, collation string, name string) (uint64, error) {
+	if name == "" {
+		return 0, nil
+	}
+	ctor := collator(collation)
+	marked := make(map[string]bool)
+	for _, item := range strings.Split(name, ",") {
+		marked[string(ctor.Key(item))] = true
+	}
+	var bits uint64
+	for i, elem := range elems {
+		key := string(ctor.Key(elem))
+		if marked[key] {
+			bits |= 1 << uint(i)
+			delete(marked, key)
+		}
+	}
+	if len(marked) == 0 {
+		return bits, nil
+	}
+	if num, err := strconv.ParseUint(name, 0, 64); err == nil {
+		set, err := types.ParseSetValue(elems, num)
+		return set.Value, err
+	}
+	return 0, errors.Errorf("item %s is not in Set %v", name, elems)
+}
+
+// SetName returns the comma separated elements of the SET value bits, in
+// declaration order.
+func SetName(elems []string, bits uint64) (string, error) {
+	set, err := types.ParseSetValue(elems, bits)
+	if err != nil {
+		return "", err
+	}
+	return set.Name, nil
+}
+
+// SetNames returns the elements of the SET value bits, in declaration order.
+func SetNames(elems []string, bits uint64) []string {
+	name, err := SetName(elems, bits)
+	if err != nil || name == "" {
+		return nil
+	}
+	return strings.Split(name, ",")
+}
+
+// SetOrdinals returns the 1-based ordinals of the elements of the SET value bits.
+func SetOrdinals(elems []string, bits uint64) []int {
+	var ordinals []int
+	for i := range elems {
+		if bits&(1<<uint(i)) != 0 {
+			ordinals = append(ordinals, i+1)
+		}
+	}
+	return ordinals
+}
+
+// SetOfOrdinals returns the SET value holding the elements of the given 1-based ordinals.
+func SetOfOrdinals(elems []string, ordinals ...int) (uint64, error) {
+	var bits uint64
+	for _, ordinal := range ordinals {
+		if ordinal < 1 || ordinal > len(elems) {
+			return 0, errors.Errorf("ordinal %d overflow set boundary [1, %d]", ordinal, len(elems))
+		}
+		bits |= 1 << uint(ordinal-1)
+	}
+	return bits, nil
+}
+
+// IsValidSet reports whether bits only holds elements of a SET.
+func IsValidSet(elems []string, bits uint64) bool {
+	_, err := SetName(elems, bits)
+	return err == nil
+}
+
+// ScanSet implements sql.Scanner for generated SET types. NULL scans as the empty set.
+func ScanSet(elems []string, collation string, src interface{}) (uint64, error) {
+	if n, ok := src.(int64); ok {
+		return uint64(n), nil
+	}
+	text, ok, err := scanText(src)
+	if err != nil || !ok {
+		return 0, errors.Annotate(err, "scan set")
+	}
+	return ParseSet(elems, collation, text)
+}
+
+// SetValue implements driver.Valuer for generated SET types.
+func SetValue(elems []string, bits uint64) (driver.Value, error) {
+	return SetName(elems, bits)
+}
+
+// MarshalSet implements json.Marshaler for generated SET types, as the
+// comma separated elements MySQL returns.
+func MarshalSet(elems []string, bits uint64) ([]byte, error) {
+	name, err := SetName(elems, bits)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(name)
+}
+
+// UnmarshalSet implements json.Unmarshaler for generated SET types.
+func UnmarshalSet(elems []string, collation string, data []byte) (uint64, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return 0, err
+	}
+	return ParseSet(elems, collation, name)
+}
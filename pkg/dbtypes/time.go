@@ -0,0 +1,243 @@
+package dbtypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/stmtctx"
+	"github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// MySQLTime is the value of a DATE, DATETIME or TIMESTAMP column as MySQL
+// stores it. Unlike time.Time it holds the zero date '0000-00-00 00:00:00',
+// dates with a zero month or day and the invalid dates non-strict SQL modes
+// accept, such as '2021-02-31', and it keeps the fractional seconds precision
+// of the column. The zero value is the zero DATETIME.
+type MySQLTime struct {
+	t types.Time
+}
+
+// lenientStmtCtx accepts what MySQL stores when no strict SQL mode is set.
+func lenientStmtCtx() *stmtctx.StatementContext {
+	return &stmtctx.StatementContext{
+		TimeZone:         time.UTC,
+		IgnoreZeroInDate: true,
+		AllowInvalidDate: true,
+	}
+}
+
+// ParseMySQLTime parses MySQL date and time text, e.g. "2021-03-04",
+// "2021-03-04 05:06:07.890" or "0000-00-00 00:00:00". Text without a time
+// part is a DATE; the fractional seconds precision is that of the text.
+func ParseMySQLTime(text string) (MySQLTime, error) {
+	tp := mysql.TypeDatetime
+	if !strings.ContainsAny(strings.TrimSpace(text), " T:") {
+		tp = mysql.TypeDate
+	}
+	t, err := types.ParseTime(lenientStmtCtx(), text, tp, types.GetFsp(text))
+	if err != nil {
+		return MySQLTime{}, errors.Annotatef(err, "parse time %q", text)
+	}
+	return MySQLTime{t: t}, nil
+}
+
+// MustParseMySQLTime is like ParseMySQLTime but panics on malformed text. It
+// is meant for constants, such as the column defaults of generated
+// constructors.
+func MustParseMySQLTime(text string) MySQLTime {
+	t, err := ParseMySQLTime(text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// MySQLTimeOf returns the DATETIME of t in its location, rounded to fsp
+// fractional digits.
+func MySQLTimeOf(t time.Time, fsp int) MySQLTime {
+	checked, err := types.CheckFsp(fsp)
+	if err != nil {
+		checked = types.MaxFsp
+	}
+	rounded, _ := types.RoundFrac(t, checked)
+	return MySQLTime{t: types.NewTime(types.FromGoTime(rounded), mysql.TypeDatetime, checked)}
+}
+
+// MySQLTimeNow returns the current DATETIME in the local time zone with fsp
+// fractional digits, as CURRENT_TIMESTAMP(fsp) does.
+func MySQLTimeNow(fsp int) MySQLTime {
+	return MySQLTimeOf(time.Now(), fsp)
+}
+
+// String returns t as MySQL writes it, e.g. "2021-03-04 05:06:07.890".
+func (t MySQLTime) String() string {
+	return t.t.String()
+}
+
+// IsZero reports whether t is the zero date '0000-00-00 00:00:00'.
+func (t MySQLTime) IsZero() bool {
+	return t.t.IsZero()
+}
+
+// HasZeroInDate reports whether the month or the day of t is 0.
+func (t MySQLTime) HasZeroInDate() bool {
+	return t.t.InvalidZero()
+}
+
+// IsDate reports whether t is a DATE, without time part.
+func (t MySQLTime) IsDate() bool {
+	return t.t.Type() == mysql.TypeDate
+}
+
+// Fsp returns the number of fractional seconds digits of t.
+func (t MySQLTime) Fsp() int {
+	return int(t.t.Fsp())
+}
+
+// Date returns the year, month and day of t, as stored.
+func (t MySQLTime) Date() (year, month, day int) {
+	ct := t.t.CoreTime()
+	return ct.Year(), ct.Month(), ct.Day()
+}
+
+// Clock returns the hour, minute, second and microsecond of t.
+func (t MySQLTime) Clock() (hour, minute, second, microsecond int) {
+	ct := t.t.CoreTime()
+	return ct.Hour(), ct.Minute(), ct.Second(), ct.Microsecond()
+}
+
+// GoTime returns t as a time.Time in loc. Zero dates and invalid dates have
+// no time.Time and return an error.
+func (t MySQLTime) GoTime(loc *time.Location) (time.Time, error) {
+	if t.IsZero() {
+		return time.Time{}, errors.New("zero date has no time.Time")
+	}
+	goTime, err := t.t.CoreTime().GoTime(loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return goTime, nil
+}
+
+// Round rounds t to fsp fractional seconds digits.
+func (t MySQLTime) Round(fsp int) (MySQLTime, error) {
+	rounded, err := t.t.RoundFrac(lenientStmtCtx(), int8(fsp))
+	if err != nil {
+		return t, err
+	}
+	return MySQLTime{t: rounded}, nil
+}
+
+// Compare returns -1, 0 or +1 as t is before, equal to or after other.
+func (t MySQLTime) Compare(other MySQLTime) int {
+	return t.t.Compare(other.t)
+}
+
+// Scan implements sql.Scanner. NULL scans as the zero date. The driver should
+// return text, e.g. with parseTime=false for go-sql-driver/mysql, as
+// time.Time cannot hold zero dates.
+func (t *MySQLTime) Scan(src interface{}) error {
+	if v, ok := src.(time.Time); ok {
+		*t = MySQLTimeOf(v, int(types.MaxFsp))
+		return nil
+	}
+	text, ok, err := scanText(src)
+	if err != nil {
+		return errors.Annotate(err, "scan time")
+	}
+	if !ok {
+		*t = MySQLTime{}
+		return nil
+	}
+	parsed, err := ParseMySQLTime(text)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Value implements driver.Valuer. Times are sent as MySQL text, which keeps
+// zero dates; storing them still depends on the SQL mode of the server.
+func (t MySQLTime) Value() (driver.Value, error) {
+	return t.String(), nil
+}
+
+// MarshalJSON implements json.Marshaler, writing t as MySQL text.
+func (t MySQLTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. MySQL text and RFC 3339 times
+// are accepted; null leaves t unchanged, as for time.Time.
+func (t *MySQLTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	parsed, err := ParseMySQLTime(text)
+	if err != nil {
+		goTime, goErr := time.Parse(time.RFC3339Nano, text)
+		if goErr != nil {
+			return err
+		}
+		fsp := 0
+		if goTime.Nanosecond() != 0 {
+			fsp = int(types.MaxFsp)
+		}
+		parsed = MySQLTimeOf(goTime, fsp)
+	}
+	*t = parsed
+	return nil
+}
+
+// NullMySQLTime is a MySQLTime that may be NULL, in the style of the
+// database/sql Null types.
+type NullMySQLTime struct {
+	MySQLTime MySQLTime
+	Valid     bool // Valid is true if MySQLTime is not NULL
+}
+
+// Scan implements sql.Scanner.
+func (n *NullMySQLTime) Scan(src interface{}) error {
+	if src == nil {
+		*n = NullMySQLTime{}
+		return nil
+	}
+	n.Valid = true
+	return n.MySQLTime.Scan(src)
+}
+
+// Value implements driver.Valuer.
+func (n NullMySQLTime) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.MySQLTime.Value()
+}
+
+// MarshalJSON implements json.Marshaler. NULL is written as null.
+func (n NullMySQLTime) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.MySQLTime.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *NullMySQLTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullMySQLTime{}
+		return nil
+	}
+	n.Valid = true
+	return n.MySQLTime.UnmarshalJSON(data)
+}
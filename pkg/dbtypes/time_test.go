@@ -0,0 +1,75 @@
+package dbtypes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMySQLTimeZeroDates(t *testing.T) {
+	for _, test := range []struct {
+		text          string
+		want          string
+		zero, zeroDay bool
+		date          bool
+	}{
+		{"0000-00-00 00:00:00", "0000-00-00 00:00:00", true, true, false},
+		{"0000-00-00", "0000-00-00", true, true, true},
+		{"2021-00-10", "2021-00-10", false, true, true},
+		{"2021-02-31 10:00:00", "2021-02-31 10:00:00", false, false, false},
+		{"2021-03-04 05:06:07.890", "2021-03-04 05:06:07.890", false, false, false},
+	} {
+		tm, err := ParseMySQLTime(test.text)
+		if err != nil {
+			t.Errorf("parse %s: %v", test.text, err)
+			continue
+		}
+		if got := tm.String(); got != test.want {
+			t.Errorf("parse %s: got %s, want %s", test.text, got, test.want)
+		}
+		if tm.IsZero() != test.zero || tm.HasZeroInDate() != test.zeroDay || tm.IsDate() != test.date {
+			t.Errorf("parse %s: got zero %v, zero in date %v, date %v", test.text,
+				tm.IsZero(), tm.HasZeroInDate(), tm.IsDate())
+		}
+	}
+	if _, err := MustParseMySQLTime("0000-00-00").GoTime(time.UTC); err == nil {
+		t.Errorf("zero date as time.Time: want an error")
+	}
+}
+
+func TestMySQLTimeJSON(t *testing.T) {
+	for _, text := range []string{"0000-00-00 00:00:00", "2021-00-10", "2021-03-04 05:06:07.890"} {
+		tm := MustParseMySQLTime(text)
+		data, err := json.Marshal(tm)
+		if err != nil {
+			t.Errorf("marshal %s: %v", text, err)
+			continue
+		}
+		var back MySQLTime
+		if err := json.Unmarshal(data, &back); err != nil {
+			t.Errorf("unmarshal %s: %v", data, err)
+			continue
+		}
+		if back.String() != text || back.Compare(tm) != 0 {
+			t.Errorf("round trip %s: got %s", text, back)
+		}
+	}
+
+	v := struct {
+		T MySQLTime `json:"t"`
+	}{T: MustParseMySQLTime("2021-03-04")}
+	if err := json.Unmarshal([]byte(`{"t":null}`), &v); err != nil {
+		t.Fatalf("unmarshal null: %v", err)
+	}
+	if got := v.T.String(); got != "2021-03-04" {
+		t.Errorf("unmarshal null: got %s, want the value unchanged", got)
+	}
+	if err := json.Unmarshal([]byte(`{"t":"2021-03-04T05:06:07Z"}`), &v); err != nil || v.T.String() != "2021-03-04 05:06:07" {
+		t.Errorf("unmarshal RFC 3339: got %s, %v", v.T, err)
+	}
+
+	n := NullMySQLTime{MySQLTime: MustParseMySQLTime("2021-03-04"), Valid: true}
+	if err := json.Unmarshal([]byte("null"), &n); err != nil || n.Valid {
+		t.Errorf("unmarshal null into NullMySQLTime: got %+v, %v, want NULL", n, err)
+	}
+}
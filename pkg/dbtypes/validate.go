@@ -0,0 +1,216 @@
+package dbtypes
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	parsertypes "github.com/pingcap/parser/types"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/stmtctx"
+	"github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// validatedTypes are the column types ValidateColumn knows, by SQL type name.
+// The string types are known by their binary names too, e.g. blob for text.
+var validatedTypes = make(map[string]byte)
+
+func init() {
+	for _, tp := range []byte{
+		mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong,
+		mysql.TypeFloat, mysql.TypeDouble, mysql.TypeNewDecimal, mysql.TypeBit, mysql.TypeYear,
+		mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration,
+		mysql.TypeString, mysql.TypeVarchar,
+		mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob,
+		mysql.TypeEnum, mysql.TypeSet,
+	} {
+		validatedTypes[parsertypes.TypeStr(tp)] = tp
+		validatedTypes[parsertypes.TypeToStr(tp, charset.CharsetBin)] = tp
+	}
+}
+
+// CanValidate reports whether ValidateColumn knows the SQL type named typeName,
+// as types.TypeToStr names it.
+func CanValidate(typeName string) bool {
+	_, ok := validatedTypes[typeName]
+	return ok
+}
+
+// ColumnType is the type of a column as ValidateColumn checks values against.
+// Generated Validate methods hold one per column.
+type ColumnType struct {
+	Type     string // SQL type name, e.g. "varchar", "blob" or "decimal"
+	Length   int    // length in characters, display width or precision; -1 when unspecified
+	Decimal  int    // scale or fractional seconds precision; -1 when unspecified
+	Unsigned bool
+	// Nullable tells that the column accepts NULL, which the zero value of
+	// a Go type without NULL, such as time.Time, stands for.
+	Nullable bool
+	// Charset is the charset of string columns. CHAR and VARCHAR lengths are
+	// counted in its characters, TEXT and BLOB lengths in bytes.
+	Charset string
+	// Elems are the elements of ENUM and SET, matched with Collation.
+	Elems     []string
+	Collation string
+}
+
+func (columnType ColumnType) fieldType() (*types.FieldType, error) {
+	tp, ok := validatedTypes[columnType.Type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported column type %s", columnType.Type)
+	}
+	ft := types.NewFieldType(tp)
+	ft.Flen, ft.Decimal = columnType.Length, columnType.Decimal
+	ft.Charset = columnType.Charset
+	ft.Elems, ft.Collate = columnType.Elems, columnType.Collation
+	if columnType.Unsigned {
+		ft.Flag |= mysql.UnsignedFlag
+	}
+	if parsertypes.IsTypeBlob(tp) {
+		// TEXT and BLOB lengths are in bytes, whatever the charset.
+		ft.Charset = ""
+	}
+	return ft, nil
+}
+
+// ColumnError is the error of a value MySQL would not store in a column.
+type ColumnError struct {
+	Column string
+	Err    error
+}
+
+func (e *ColumnError) Error() string {
+	reason := "Incorrect value"
+	switch {
+	case types.ErrDataTooLong.Equal(e.Err):
+		reason = "Data too long"
+	case types.ErrOverflow.Equal(e.Err), types.ErrWarnDataOutOfRange.Equal(e.Err):
+		reason = "Out of range value"
+	}
+	return fmt.Sprintf("%s for column '%s': %v", reason, e.Column, e.Err)
+}
+
+func (e *ColumnError) Unwrap() error {
+	return e.Err
+}
+
+// validateStmtCtx converts values as an INSERT does in strict SQL mode:
+// overflows and truncated data are errors, extra decimal digits are rounded.
+func validateStmtCtx() *stmtctx.StatementContext {
+	return &stmtctx.StatementContext{TimeZone: time.UTC, InInsertStmt: true}
+}
+
+// ValidateColumn returns an error when MySQL in strict SQL mode would reject
+// v as a value of column, as for a string longer than the column or a number
+// out of its range. v is taken as CheckValueOf takes it; NULL is accepted, as
+// is the zero value of v when the column is Nullable.
+func ValidateColumn(column string, v interface{}, columnType ColumnType) error {
+	var d types.Datum
+	if err := setDatum(&d, v, ""); err != nil {
+		return &ColumnError{Column: column, Err: err}
+	}
+	if d.IsNull() || columnType.Nullable && reflect.ValueOf(v).IsZero() {
+		return nil
+	}
+	ft, err := columnType.fieldType()
+	if err != nil {
+		return &ColumnError{Column: column, Err: err}
+	}
+	sc := validateStmtCtx()
+	switch ft.Tp {
+	case mysql.TypeEnum:
+		err = validateEnum(sc, d, ft)
+	case mysql.TypeSet:
+		err = validateSet(sc, d, ft)
+	case mysql.TypeFloat, mysql.TypeDouble:
+		err = validateFloat(sc, d, ft, columnType.Type)
+	case mysql.TypeYear:
+		err = validateYear(sc, d, ft)
+	default:
+		var converted types.Datum
+		converted, err = d.ConvertTo(sc, ft)
+		if err == nil && types.IsTypeTime(ft.Tp) {
+			t := converted.GetMysqlTime()
+			if t.Check(sc) != nil {
+				err = types.ErrWrongValue.GenWithStackByArgs(columnType.Type, t.String())
+			}
+		}
+	}
+	if err != nil {
+		return &ColumnError{Column: column, Err: err}
+	}
+	return nil
+}
+
+// validateFloat checks a FLOAT or DOUBLE value. Datum.ConvertTo lets NaN and
+// the infinities through, which MySQL has no value for, and leaves the range
+// of FLOAT to float32.
+func validateFloat(sc *stmtctx.StatementContext, d types.Datum, ft *types.FieldType, typeName string) error {
+	converted, err := d.ConvertTo(sc, ft)
+	if err != nil {
+		return err
+	}
+	f := converted.GetFloat64()
+	if ft.Tp == mysql.TypeFloat {
+		f = float64(converted.GetFloat32())
+	}
+	switch {
+	case math.IsNaN(f):
+		return types.ErrWrongValue.GenWithStackByArgs(typeName, "NaN")
+	case math.IsInf(f, 0):
+		return types.ErrOverflow.GenWithStack("constant %v overflows %s", d.GetValue(), typeName)
+	}
+	return nil
+}
+
+// validateYear checks a YEAR value: 0, a two-digit year or a year from 1901 to
+// 2155. Datum.ConvertTo reports the years out of that range as values it
+// cannot convert.
+func validateYear(sc *stmtctx.StatementContext, d types.Datum, ft *types.FieldType) error {
+	_, err := d.ConvertTo(sc, ft)
+	if err == nil {
+		return nil
+	}
+	if y, intErr := d.ToInt64(sc); intErr == nil {
+		if _, yearErr := types.AdjustYear(y, false); yearErr != nil {
+			return types.ErrWarnDataOutOfRange.GenWithStack("year %d is not between %d and %d", y, types.MinYear, types.MaxYear)
+		}
+	}
+	return err
+}
+
+// validateEnum checks an ENUM value, an element name or a 1-based ordinal.
+// Datum.ConvertTo would log the values it rejects, and match names with the
+// collation only when the new collations are enabled.
+func validateEnum(sc *stmtctx.StatementContext, d types.Datum, ft *types.FieldType) error {
+	switch d.Kind() {
+	case types.KindString, types.KindBytes:
+		_, err := ParseEnum(ft.Elems, ft.Collate, d.GetString())
+		return err
+	}
+	n, err := d.ToInt64(sc)
+	if err != nil {
+		return err
+	}
+	_, err = types.ParseEnumValue(ft.Elems, uint64(n))
+	return err
+}
+
+// validateSet checks a SET value, comma separated element names or the bits
+// themselves, as validateEnum does an ENUM.
+func validateSet(sc *stmtctx.StatementContext, d types.Datum, ft *types.FieldType) error {
+	switch d.Kind() {
+	case types.KindString, types.KindBytes:
+		_, err := ParseSet(ft.Elems, ft.Collate, d.GetString())
+		return err
+	}
+	n, err := d.ToInt64(sc)
+	if err != nil {
+		return err
+	}
+	_, err = types.ParseSetValue(ft.Elems, uint64(n))
+	return err
+}
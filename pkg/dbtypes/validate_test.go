@@ -0,0 +1,81 @@
+package dbtypes
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestValidateColumn(t *testing.T) {
+	varchar := ColumnType{Type: "varchar", Length: 3, Decimal: -1, Charset: "utf8mb4"}
+	text := ColumnType{Type: "tinytext", Length: 255, Decimal: -1, Charset: "utf8mb4"}
+	timestamp := ColumnType{Type: "timestamp", Length: 19, Decimal: 0}
+	float := ColumnType{Type: "float", Length: 12, Decimal: -1}
+	year := ColumnType{Type: "year", Length: 4, Decimal: 0}
+	blob := ColumnType{Type: "tinyblob", Length: 255, Decimal: -1, Charset: "binary"}
+	for _, test := range []struct {
+		name       string
+		v          interface{}
+		columnType ColumnType
+		ok         bool
+	}{
+		{"varchar in characters", "日本語", varchar, true},
+		{"varchar too long", "abcd", varchar, false},
+		{"text in bytes", string(make([]byte, 255)), text, true},
+		{"text too long in bytes", string([]rune{'語'}) + string(make([]byte, 253)), text, false},
+		{"tinyint", int64(127), ColumnType{Type: "tinyint", Length: 4, Decimal: 0}, true},
+		{"tinyint out of range", int64(128), ColumnType{Type: "tinyint", Length: 4, Decimal: 0}, false},
+		{"unsigned negative", int64(-1), ColumnType{Type: "int", Length: 10, Decimal: 0, Unsigned: true}, false},
+		{"float", 3.5, float, true},
+		{"float max", float64(math.MaxFloat32), float, true},
+		{"float out of range", 1e300, float, false},
+		{"float NaN", math.NaN(), float, false},
+		{"float infinity", math.Inf(-1), float, false},
+		{"double", 1e300, ColumnType{Type: "double", Length: 22, Decimal: -1}, true},
+		{"double NaN", math.NaN(), ColumnType{Type: "double", Length: 22, Decimal: -1}, false},
+		{"double infinity", math.Inf(1), ColumnType{Type: "double", Length: 22, Decimal: -1}, false},
+		{"year", int64(2155), year, true},
+		{"year two digits", int64(69), year, true},
+		{"year zero", int64(0), year, true},
+		{"year too late", int64(2156), year, false},
+		{"year too early", int64(1900), year, false},
+		{"blob in bytes", string(make([]byte, 255)), blob, true},
+		{"blob too long", string(make([]byte, 256)), blob, false},
+		{"varbinary", []byte("abc"), ColumnType{Type: "varbinary", Length: 3, Decimal: -1, Charset: "binary"}, true},
+		{"decimal", MustParseDecimal("999.99"), ColumnType{Type: "decimal", Length: 5, Decimal: 2}, true},
+		{"decimal out of range", MustParseDecimal("1000"), ColumnType{Type: "decimal", Length: 5, Decimal: 2}, false},
+		{"timestamp", time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), timestamp, true},
+		{"timestamp out of range", time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC), timestamp, false},
+		{"zero timestamp NOT NULL", time.Time{}, timestamp, false},
+		{"zero timestamp NULL", time.Time{}, ColumnType{Type: "timestamp", Length: 19, Decimal: 0, Nullable: true}, true},
+		{"nil pointer", (*time.Time)(nil), timestamp, true},
+		{"enum", "s", ColumnType{Type: "enum", Length: -1, Decimal: -1, Elems: []string{"S", "M"}, Collation: "utf8mb4_general_ci"}, true},
+		{"enum not an element", "L", ColumnType{Type: "enum", Length: -1, Decimal: -1, Elems: []string{"S", "M"}, Collation: "utf8mb4_general_ci"}, false},
+	} {
+		err := ValidateColumn("c", test.v, test.columnType)
+		if test.ok && err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if !test.ok && err == nil {
+			t.Errorf("%s: want an error", test.name)
+		}
+	}
+}
+
+func TestValidateColumnErrors(t *testing.T) {
+	for _, test := range []struct {
+		v          interface{}
+		columnType ColumnType
+		err        string
+	}{
+		{math.NaN(), ColumnType{Type: "double", Length: 22, Decimal: -1}, "Incorrect value for column 'c': [types:1292]Incorrect double value: 'NaN'"},
+		{math.Inf(1), ColumnType{Type: "double", Length: 22, Decimal: -1}, "Out of range value for column 'c': [types:1690]constant +Inf overflows double"},
+		{int64(2156), ColumnType{Type: "year", Length: 4, Decimal: 0}, "Out of range value for column 'c': [types:1264]year 2156 is not between 1901 and 2155"},
+		{"2156", ColumnType{Type: "year", Length: 4, Decimal: 0}, "Out of range value for column 'c': [types:1264]year 2156 is not between 1901 and 2155"},
+		{string(make([]byte, 256)), ColumnType{Type: "tinyblob", Length: 255, Decimal: -1, Charset: "binary"}, "Data too long for column 'c': [types:1406]Data Too Long, field len 255, data len 256"},
+	} {
+		err := ValidateColumn("c", test.v, test.columnType)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%s %v: got %v, want %s", test.columnType.Type, test.v, err, test.err)
+		}
+	}
+}
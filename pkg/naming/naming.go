@@ -0,0 +1,153 @@
+// Package naming turns SQL names into Go identifiers: camel case words,
+// golint initialisms, escaped keywords and, optionally, pinyin for Chinese
+// characters.
+package naming
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/iancoleman/strcase"
+)
+
+// CommonInitialisms are the initialisms of golint, spelled in capitals in Go
+// names, e.g. UserID rather than UserId.
+var CommonInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP",
+	"HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA",
+	"SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID",
+	"URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+// Namer derives Go names from SQL names. The zero Namer camel cases names as
+// strcase.ToCamel does, keeping the letters it would drop.
+type Namer struct {
+	// Initialisms are the words spelled in capitals, in upper case, e.g.
+	// CommonInitialisms.
+	Initialisms map[string]bool
+	// Pinyin transliterates Chinese characters, so that 用户 gives YongHu.
+	Pinyin bool
+}
+
+// NewNamer returns a Namer spelling initialisms in capitals, or none when
+// initialisms is empty.
+func NewNamer(initialisms []string, pinyin bool) Namer {
+	namer := Namer{Pinyin: pinyin}
+	if len(initialisms) > 0 {
+		namer.Initialisms = make(map[string]bool, len(initialisms))
+		for _, initialism := range initialisms {
+			namer.Initialisms[strings.ToUpper(initialism)] = true
+		}
+	}
+	return namer
+}
+
+// Camel returns name in camel case, without the characters Go identifiers
+// cannot hold. It may be empty, or start with a digit or a letter without
+// case.
+func (namer Namer) Camel(name string) string {
+	if namer.Pinyin {
+		name = Transliterate(name)
+	}
+	var camel strings.Builder
+	for name != "" {
+		// strcase drops the letters outside ASCII, which are kept as they are.
+		i := strings.IndexFunc(name, isNonASCIILetter)
+		if i < 0 {
+			i = len(name)
+		}
+		camel.WriteString(strcase.ToCamel(name[:i]))
+		name = name[i:]
+		if name != "" {
+			r, size := utf8.DecodeRuneInString(name)
+			camel.WriteRune(r)
+			name = name[size:]
+		}
+	}
+	ident := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, camel.String())
+	if len(namer.Initialisms) == 0 {
+		return ident
+	}
+	words := Words(ident)
+	for i, word := range words {
+		if upper := strings.ToUpper(word); namer.Initialisms[upper] {
+			words[i] = upper
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// Exported returns the exported Go name of name. Names that would not be
+// exported, as they start with a digit or a letter without case, or that are
+// empty, are prefixed with X.
+func (namer Namer) Exported(name string) string {
+	return Export(namer.Camel(name))
+}
+
+// Export prefixes ident with X unless it is exported.
+func Export(ident string) string {
+	if r, _ := utf8.DecodeRuneInString(ident); !unicode.IsUpper(r) {
+		ident = "X" + ident
+	}
+	return ident
+}
+
+// Unexported returns the unexported Go name of name, e.g. for a variable:
+// its first word in lower case, escaped by Escape.
+func (namer Namer) Unexported(name string) string {
+	words := Words(namer.Camel(name))
+	if len(words) > 0 {
+		words[0] = strings.ToLower(words[0])
+	}
+	return Escape(strings.Join(words, ""))
+}
+
+// Words splits a camel case name into its words, keeping runs of capitals
+// together: UserURLPath gives User, URL and Path. Digits belong to the word
+// they follow.
+func Words(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) &&
+			(!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// Escape turns name into a valid Go identifier: characters identifiers
+// cannot hold become underscores, a leading digit gets an underscore prefix
+// and keywords an underscore suffix.
+func Escape(name string) string {
+	ident := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	if r, _ := utf8.DecodeRuneInString(ident); ident == "" || unicode.IsDigit(r) {
+		ident = "_" + ident
+	}
+	if token.IsKeyword(ident) {
+		ident += "_"
+	}
+	return ident
+}
+
+func isNonASCIILetter(r rune) bool {
+	return r > unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
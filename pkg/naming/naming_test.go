@@ -0,0 +1,120 @@
+package naming
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNamer(t *testing.T) {
+	plain := Namer{}
+	initialisms := NewNamer(CommonInitialisms, false)
+	pinyin := NewNamer(CommonInitialisms, true)
+	for _, test := range []struct {
+		namer      Namer
+		name       string
+		camel      string
+		exported   string
+		unexported string
+	}{
+		{plain, "user_name", "UserName", "UserName", "userName"},
+		{plain, "user_id", "UserId", "UserId", "userId"},
+		{plain, "userName", "UserName", "UserName", "userName"},
+		{plain, "order-items.v2", "OrderItemsV2", "OrderItemsV2", "orderItemsV2"},
+		{initialisms, "user_id", "UserID", "UserID", "userID"},
+		{initialisms, "avatar_url", "AvatarURL", "AvatarURL", "avatarURL"},
+		{initialisms, "id", "ID", "ID", "id"},
+		{initialisms, "uuid_str", "UUIDStr", "UUIDStr", "uuidStr"},
+		{initialisms, "identity", "Identity", "Identity", "identity"},
+		// Keywords are escaped once unexported.
+		{plain, "type", "Type", "Type", "type_"},
+		{plain, "func", "Func", "Func", "func_"},
+		// Names that cannot start an exported identifier.
+		{plain, "1st_place", "1StPlace", "X1StPlace", "_1StPlace"},
+		{plain, "_", "", "X", "_"},
+		{plain, "名称", "名称", "X名称", "名称"},
+		// Letters outside ASCII are kept.
+		{plain, "user名称", "User名称", "User名称", "user名称"},
+		{plain, "café_order", "CaféOrder", "CaféOrder", "caféOrder"},
+		{pinyin, "用户名", "YongHuMing", "YongHuMing", "yongHuMing"},
+		{pinyin, "用户表", "YongHuBiao", "YongHuBiao", "yongHuBiao"},
+		{pinyin, "用户id", "YongHuID", "YongHuID", "yongHuID"},
+		{pinyin, "user_名称", "UserMingCheng", "UserMingCheng", "userMingCheng"},
+	} {
+		if got := test.namer.Camel(test.name); got != test.camel {
+			t.Errorf("Camel(%q): got %s, want %s", test.name, got, test.camel)
+		}
+		if got := test.namer.Exported(test.name); got != test.exported {
+			t.Errorf("Exported(%q): got %s, want %s", test.name, got, test.exported)
+		}
+		if got := test.namer.Unexported(test.name); got != test.unexported {
+			t.Errorf("Unexported(%q): got %s, want %s", test.name, got, test.unexported)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	for name, words := range map[string][]string{
+		"":             nil,
+		"User":         {"User"},
+		"UserURLPath":  {"User", "URL", "Path"},
+		"HTTPServer":   {"HTTP", "Server"},
+		"OrderItemsV2": {"Order", "Items", "V2"},
+		"UserID":       {"User", "ID"},
+		"utf8Name":     {"utf8", "Name"},
+	} {
+		if got := Words(name); !reflect.DeepEqual(got, words) {
+			t.Errorf("Words(%q): got %q, want %q", name, got, words)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	for name, ident := range map[string]string{
+		"user":     "user",
+		"":         "_",
+		"1st":      "_1st",
+		"range":    "range_",
+		"a-b c":    "a_b_c",
+		"名称":       "名称",
+		"Export":   "Export",
+		"int":      "int",
+		"user.id!": "user_id_",
+	} {
+		if got := Escape(name); got != ident {
+			t.Errorf("Escape(%q): got %s, want %s", name, got, ident)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	for ident, exported := range map[string]string{
+		"User":  "User",
+		"user":  "Xuser",
+		"_user": "X_user",
+		"":      "X",
+		"Éclat": "Éclat",
+	} {
+		if got := Export(ident); got != exported {
+			t.Errorf("Export(%q): got %s, want %s", ident, got, exported)
+		}
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	for s, want := range map[string]string{
+		"用户名":     "yong_hu_ming",
+		"user_名称": "user_ming_cheng",
+		"用户id":    "yong_hu_id",
+		"id用户":    "id_yong_hu",
+		"用户_id":   "yong_hu_id",
+		"地址":      "di_zhi",
+		"长度":      "chang_du",
+		"plain":   "plain",
+		"café":    "café",
+		"订单2":     "ding_dan_2",
+	} {
+		if got := Transliterate(s); got != want {
+			t.Errorf("Transliterate(%q): got %s, want %s", s, got, want)
+		}
+	}
+}
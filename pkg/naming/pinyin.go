@@ -0,0 +1,56 @@
+package naming
+
+import (
+	"strings"
+	"sync"
+)
+
+// pinyinOverrides are the readings of characters more common in names than
+// the one of pinyinTable.
+var pinyinOverrides = map[rune]string{
+	'地': "di",
+	'长': "chang",
+	'似': "si",
+}
+
+var (
+	pinyinOnce sync.Once
+	pinyin     map[rune]string
+)
+
+// Transliterate replaces the Chinese characters of s with their toneless
+// pinyin, as separate words: 用户名 gives "yong_hu_ming". Characters the table
+// does not know are kept.
+func Transliterate(s string) string {
+	pinyinOnce.Do(func() {
+		pinyin = make(map[rune]string, 21000)
+		for _, entry := range pinyinTable {
+			for _, r := range entry.chars {
+				pinyin[r] = entry.syllable
+			}
+		}
+		for r, syllable := range pinyinOverrides {
+			pinyin[r] = syllable
+		}
+	})
+	var b strings.Builder
+	// Syllables are separated from what precedes and follows them.
+	separated, pending := true, false
+	for _, r := range s {
+		syllable, ok := pinyin[r]
+		if !ok {
+			if pending && r != '_' {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			separated, pending = r == '_', false
+			continue
+		}
+		if !separated {
+			b.WriteByte('_')
+		}
+		b.WriteString(syllable)
+		separated, pending = false, true
+	}
+	return b.String()
+}
@@ -0,0 +1,192 @@
+package parser
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+)
+
+// parseAlterTableStmt replays the specs of an ALTER TABLE statement in order
+// against the table built so far, so that the generated struct matches the
+// schema after the whole file has run.
+func (parser *DDLParser) parseAlterTableStmt(stmt *ast.AlterTableStmt) error {
+	tableName := stmt.Table.Name.String()
+	_, table := parser.findTable(tableName)
+	if table == nil {
+		return errors.Errorf("alter unknown table :%s", tableName)
+	}
+	parser.touchTable(table)
+	for _, spec := range stmt.Specs {
+		if err := parser.alterTable(table, spec); err != nil {
+			return errors.Annotatef(err, "alter table %s", tableName)
+		}
+		if spec.Tp == ast.AlterTableRenameTable {
+			tableName = table.TableName
+		}
+	}
+	return nil
+}
+
+func (parser *DDLParser) alterTable(table *Table, spec *ast.AlterTableSpec) error {
+	switch spec.Tp {
+	case ast.AlterTableOption:
+		for _, option := range spec.Options {
+			if option.Tp == ast.TableOptionComment {
+				table.TableComment = option.StrValue
+			}
+		}
+	case ast.AlterTableRenameTable:
+		return parser.renameTable(table.TableName, spec.NewTable.Name.String())
+	case ast.AlterTableAddColumns:
+		for _, col := range spec.NewColumns {
+			name := col.Name.Name.String()
+			if table.Columns.Find(name) >= 0 {
+				if spec.IfNotExists {
+					continue
+				}
+				return errors.Errorf("duplicate column name :%s", name)
+			}
+			if err := parser.placeColumn(table, col, spec.Position); err != nil {
+				return err
+			}
+			if err := parser.addColumnIndexes(table, col); err != nil {
+				return err
+			}
+			if err := parser.addColumnChecks(table, col); err != nil {
+				return err
+			}
+		}
+		for _, constraint := range spec.NewConstraints {
+			if err := parser.addConstraint(table, constraint); err != nil {
+				return err
+			}
+		}
+	case ast.AlterTableDropColumn:
+		name := spec.OldColumnName.Name.String()
+		pos := table.Columns.Find(name)
+		if pos < 0 {
+			if spec.IfExists {
+				return nil
+			}
+			return errors.Errorf("can't drop column :%s; check that it exists", name)
+		}
+		if err := table.checkDropColumn(name); err != nil {
+			return err
+		}
+		table.Columns = table.Columns.Remove(pos)
+		table.dropIndexColumn(name)
+	case ast.AlterTableModifyColumn, ast.AlterTableChangeColumn:
+		name := spec.NewColumns[0].Name.Name.String()
+		if spec.OldColumnName != nil {
+			name = spec.OldColumnName.Name.String()
+		}
+		pos := table.Columns.Find(name)
+		if pos < 0 {
+			if spec.IfExists {
+				return nil
+			}
+			return errors.Errorf("unknown column :%s", name)
+		}
+		newName := spec.NewColumns[0].Name.Name.String()
+		if other := table.Columns.Find(newName); other >= 0 && other != pos {
+			return errors.Errorf("duplicate column name :%s", newName)
+		}
+		if spec.Position == nil || spec.Position.Tp == ast.ColumnPositionNone {
+			column, err := parser.newColumn(spec.NewColumns[0])
+			if err != nil {
+				return err
+			}
+			table.Columns[pos] = column
+		} else {
+			table.Columns = table.Columns.Remove(pos)
+			if err := parser.placeColumn(table, spec.NewColumns[0], spec.Position); err != nil {
+				return err
+			}
+		}
+		table.renameIndexColumn(name, newName)
+		table.renameForeignKeyColumn(name, newName)
+		parser.renameReferencedColumn(table.TableName, name, newName)
+		if err := parser.addColumnIndexes(table, spec.NewColumns[0]); err != nil {
+			return err
+		}
+		return parser.addColumnChecks(table, spec.NewColumns[0])
+	case ast.AlterTableRenameColumn:
+		name := spec.OldColumnName.Name.String()
+		pos := table.Columns.Find(name)
+		if pos < 0 {
+			return errors.Errorf("unknown column :%s", name)
+		}
+		newName := spec.NewColumnName.Name.String()
+		if other := table.Columns.Find(newName); other >= 0 && other != pos {
+			return errors.Errorf("duplicate column name :%s", newName)
+		}
+		table.Columns[pos].Name = newName
+		table.renameIndexColumn(name, newName)
+		table.renameForeignKeyColumn(name, newName)
+		parser.renameReferencedColumn(table.TableName, name, newName)
+	case ast.AlterTableAlterColumn:
+		col := spec.NewColumns[0]
+		pos := table.Columns.Find(col.Name.Name.String())
+		if pos < 0 {
+			return errors.Errorf("unknown column :%s", col.Name.Name.String())
+		}
+		column := &table.Columns[pos]
+		column.DefaultSQL, column.DefaultVal, column.DefaultNow = "", "", false
+		column.defaultDatum = nil
+		// SET DEFAULT carries a single option holding the new value, DROP DEFAULT none.
+		if len(col.Options) > 0 {
+			return parser.evalDefault(column, col.Options[0].Expr)
+		}
+	case ast.AlterTableAddConstraint:
+		return parser.addConstraint(table, spec.Constraint)
+	case ast.AlterTableDropIndex:
+		return table.dropIndex(spec.Name, spec.IfExists)
+	case ast.AlterTableDropCheck:
+		return table.dropCheck(spec.Constraint.Name)
+	case ast.AlterTableAlterCheck:
+		return table.enforceCheck(spec.Constraint.Name, spec.Constraint.Enforced)
+	case ast.AlterTableDropForeignKey:
+		return table.dropForeignKey(spec.Name, spec.IfExists)
+	case ast.AlterTableDropPrimaryKey:
+		return table.dropIndex(primaryKeyName, false)
+	case ast.AlterTableRenameIndex:
+		pos := table.Indexes.Find(spec.FromKey.O)
+		if pos < 0 {
+			return errors.Errorf("key %s doesn't exist in table", spec.FromKey.O)
+		}
+		if other := table.Indexes.Find(spec.ToKey.O); other >= 0 && other != pos {
+			return errors.Errorf("duplicate key name :%s", spec.ToKey.O)
+		}
+		table.Indexes[pos].Name = spec.ToKey.O
+	case ast.AlterTableIndexInvisible:
+		pos := table.Indexes.Find(spec.Name)
+		if pos < 0 {
+			return errors.Errorf("key %s doesn't exist in table", spec.Name)
+		}
+		table.Indexes[pos].Invisible = spec.Visibility == ast.IndexVisibilityInvisible
+	}
+	return nil
+}
+
+// placeColumn inserts a new column according to a FIRST / AFTER clause.
+func (parser *DDLParser) placeColumn(table *Table, col *ast.ColumnDef, position *ast.ColumnPosition) error {
+	column, err := parser.newColumn(col)
+	if err != nil {
+		return err
+	}
+	pos := len(table.Columns)
+	if position != nil {
+		switch position.Tp {
+		case ast.ColumnPositionFirst:
+			pos = 0
+		case ast.ColumnPositionAfter:
+			name := position.RelativeColumn.Name.String()
+			after := table.Columns.Find(name)
+			if after < 0 {
+				return errors.Errorf("unknown column :%s", name)
+			}
+			pos = after + 1
+		}
+	}
+	table.Columns = table.Columns.Insert(pos, column)
+	return nil
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const alterTable = "CREATE TABLE t (id int PRIMARY KEY, name varchar(32), age int DEFAULT 1, KEY idx_name (name));\n"
+
+// columnNames returns the names of the columns of table, in order.
+func columnNames(table *Table) []string {
+	names := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+func TestAlterColumns(t *testing.T) {
+	for _, test := range []struct {
+		alter   string
+		columns []string
+	}{
+		{"ALTER TABLE t ADD COLUMN email varchar(64)", []string{"id", "name", "age", "email"}},
+		{"ALTER TABLE t ADD COLUMN email varchar(64) FIRST", []string{"email", "id", "name", "age"}},
+		{"ALTER TABLE t ADD COLUMN email varchar(64) AFTER id", []string{"id", "email", "name", "age"}},
+		{"ALTER TABLE t ADD COLUMN (a int, b int)", []string{"id", "name", "age", "a", "b"}},
+		{"ALTER TABLE t ADD COLUMN IF NOT EXISTS name int", []string{"id", "name", "age"}},
+		{"ALTER TABLE t DROP COLUMN age", []string{"id", "name"}},
+		{"ALTER TABLE t DROP COLUMN IF EXISTS nope", []string{"id", "name", "age"}},
+		{"ALTER TABLE t MODIFY COLUMN age bigint", []string{"id", "name", "age"}},
+		{"ALTER TABLE t MODIFY COLUMN age bigint FIRST", []string{"age", "id", "name"}},
+		{"ALTER TABLE t CHANGE COLUMN age years smallint", []string{"id", "name", "years"}},
+		{"ALTER TABLE t CHANGE COLUMN age years smallint AFTER id", []string{"id", "years", "name"}},
+		{"ALTER TABLE t RENAME COLUMN name TO full_name", []string{"id", "full_name", "age"}},
+		{"ALTER TABLE t ADD COLUMN a int, DROP COLUMN a", []string{"id", "name", "age"}},
+	} {
+		parser := parseSQL(t, nil, alterTable+test.alter)
+		if got := columnNames(tableOf(t, parser, "t")); !reflect.DeepEqual(got, test.columns) {
+			t.Errorf("%s: got columns %v, want %v", test.alter, got, test.columns)
+		}
+	}
+}
+
+func TestAlterColumnTypes(t *testing.T) {
+	parser := parseSQL(t, nil, alterTable+"ALTER TABLE t MODIFY COLUMN age bigint NOT NULL, CHANGE COLUMN name title varchar(8)")
+	table := tableOf(t, parser, "t")
+	age := columnOf(t, table, "age")
+	if age.BaseType != "int64" || !age.NotNull || age.DefaultSQL != "" {
+		t.Errorf("modified age: got type %s, not null %v, default %q", age.BaseType, age.NotNull, age.DefaultSQL)
+	}
+	if title := columnOf(t, table, "title"); title.FieldType.Flen != 8 {
+		t.Errorf("changed title: got length %d, want 8", title.FieldType.Flen)
+	}
+	if index := table.Indexes[table.Indexes.Find("idx_name")]; !reflect.DeepEqual(index.ColumnNames(), []string{"title"}) {
+		t.Errorf("changed title: got idx_name columns %v, want title", index.ColumnNames())
+	}
+}
+
+func TestAlterDefaults(t *testing.T) {
+	parser := parseSQL(t, nil, alterTable+"ALTER TABLE t ALTER COLUMN age SET DEFAULT 42, ALTER COLUMN name SET DEFAULT 'x'")
+	table := tableOf(t, parser, "t")
+	if age := columnOf(t, table, "age"); age.DefaultSQL != "42" {
+		t.Errorf("SET DEFAULT 42: got %q", age.DefaultSQL)
+	}
+	if name := columnOf(t, table, "name"); name.DefaultSQL != "'x'" {
+		t.Errorf("SET DEFAULT 'x': got %q", name.DefaultSQL)
+	}
+
+	parser = parseSQL(t, nil, alterTable+"ALTER TABLE t ALTER COLUMN age DROP DEFAULT")
+	if age := columnOf(t, tableOf(t, parser, "t"), "age"); age.DefaultSQL != "" || age.DefaultVal != "" {
+		t.Errorf("DROP DEFAULT: got %q, %q", age.DefaultSQL, age.DefaultVal)
+	}
+}
+
+func TestAlterIndexes(t *testing.T) {
+	for _, test := range []struct {
+		alter   string
+		indexes []string
+	}{
+		{"ALTER TABLE t ADD INDEX idx_age (age)", []string{"PRIMARY", "idx_name", "idx_age"}},
+		{"ALTER TABLE t ADD UNIQUE KEY uk_name_age (name, age)", []string{"PRIMARY", "idx_name", "uk_name_age"}},
+		{"ALTER TABLE t DROP INDEX idx_name", []string{"PRIMARY"}},
+		{"ALTER TABLE t DROP PRIMARY KEY", []string{"idx_name"}},
+		{"ALTER TABLE t RENAME INDEX idx_name TO idx_title", []string{"PRIMARY", "idx_title"}},
+		{"ALTER TABLE t DROP COLUMN name", []string{"PRIMARY"}},
+		{"CREATE INDEX idx_age ON t (age)", []string{"PRIMARY", "idx_name", "idx_age"}},
+		{"DROP INDEX idx_name ON t", []string{"PRIMARY"}},
+	} {
+		parser := parseSQL(t, nil, alterTable+test.alter)
+		var got []string
+		for _, index := range tableOf(t, parser, "t").Indexes {
+			got = append(got, index.Name)
+		}
+		if !reflect.DeepEqual(got, test.indexes) {
+			t.Errorf("%s: got indexes %v, want %v", test.alter, got, test.indexes)
+		}
+	}
+}
+
+func TestAlterErrors(t *testing.T) {
+	for _, test := range []struct {
+		alter string
+		err   string
+	}{
+		{"ALTER TABLE nope ADD COLUMN a int", "alter unknown table :nope"},
+		{"ALTER TABLE t ADD COLUMN name int", "duplicate column name :name"},
+		{"ALTER TABLE t DROP COLUMN nope", "can't drop column :nope"},
+		{"ALTER TABLE t MODIFY COLUMN nope int", "unknown column :nope"},
+		{"ALTER TABLE t CHANGE COLUMN nope a int", "unknown column :nope"},
+		{"ALTER TABLE t CHANGE COLUMN age name int", "duplicate column name :name"},
+		{"ALTER TABLE t RENAME COLUMN nope TO a", "unknown column :nope"},
+		{"ALTER TABLE t ALTER COLUMN nope SET DEFAULT 1", "unknown column :nope"},
+		{"ALTER TABLE t DROP INDEX nope", "nope"},
+	} {
+		err := New("test.sql", "", "test").ParseInputs(Input{File: "test.sql", SQL: alterTable + test.alter})
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got %v, want %s", test.alter, err, test.err)
+		}
+	}
+}
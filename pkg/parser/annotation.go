@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"regexp"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+)
+
+// annotationRegex matches the annotations of a column comment, "@key=value"
+// after a space or at the start, e.g. "profile of the user
+// @type=example.com/model.Profile". The value may be empty, as in "@json=".
+// A bare "@key" is free text, as in "see @type docs", and not an annotation.
+var annotationRegex = regexp.MustCompile(`(?:^|\s)@([a-zA-Z][a-zA-Z0-9_-]*)=(\S*)`)
+
+// parseAnnotations returns the annotations of a column comment, keyed by name.
+func parseAnnotations(comment string) map[string]string {
+	matches := annotationRegex.FindAllStringSubmatch(comment, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	annotations := make(map[string]string, len(matches))
+	for _, match := range matches {
+		annotations[match[1]] = match[2]
+	}
+	return annotations
+}
+
+// applyAnnotations applies the annotations of the column comment:
+//
+//	@type=import/path.Name  use a user-defined Go type for the column; JSON
+//	                        columns are then (de)serialized as JSON by GORM
+//	@json=-                 set a struct tag of the field, json here, in place
+//	                        of the one of its tag family; an empty value
+//	                        removes it, see StructTag
+func (parser *DDLParser) applyAnnotations(column *Column) error {
+	column.Annotations = parseAnnotations(column.Comment)
+	if goType, ok := column.Annotations["type"]; ok {
+		if goType == "" {
+			return errors.Errorf("empty @type annotation on column %s", column.Name)
+		}
+		qualified, err := parser.qualifyType(goType)
+		if err != nil {
+			return errors.Annotatef(err, "column %s", column.Name)
+		}
+		column.BaseType = qualified
+		if column.FieldType.Tp == mysql.TypeJSON {
+			column.Serializer = "json"
+		}
+	}
+	return nil
+}
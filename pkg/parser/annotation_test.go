@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	for _, test := range []struct {
+		comment string
+		want    map[string]string
+	}{
+		{"user profile @type=example.com/model.Profile", map[string]string{"type": "example.com/model.Profile"}},
+		{"@json=- @validate=", map[string]string{"json": "-", "validate": ""}},
+		{"see @type docs", nil},
+		{"mail user@example.com or @admin", nil},
+		{"@validate=omitempty,email", map[string]string{"validate": "omitempty,email"}},
+	} {
+		if got := parseAnnotations(test.comment); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.comment, got, test.want)
+		}
+	}
+}
+
+func TestFreeTextAnnotations(t *testing.T) {
+	parser := parseSQL(t, nil, "CREATE TABLE t (id int PRIMARY KEY COMMENT 'see @type docs and @json', "+
+		"secret varchar(8) COMMENT 'hidden @json=-')")
+	table := tableOf(t, parser, "t")
+	id := columnOf(t, table, "id")
+	if id.BaseType != "int32" {
+		t.Errorf("id: got type %s, want int32", id.BaseType)
+	}
+	if got := table.StructTag(*id); !strings.HasPrefix(got, `json:"id" gorm:"column:id;`) {
+		t.Errorf("id: got tag %s", got)
+	}
+	if got := table.StructTag(*columnOf(t, table, "secret")); !strings.HasPrefix(got, `json:"-" gorm:"column:secret;`) {
+		t.Errorf("secret: got tag %s", got)
+	}
+
+	if err := New("test.sql", "", "test").ParseInputs(Input{File: "test.sql", SQL: "CREATE TABLE t (j json COMMENT '@type=')"}); err == nil {
+		t.Errorf("empty @type=: want an error")
+	}
+}
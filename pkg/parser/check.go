@@ -0,0 +1,298 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/opcode"
+	"github.com/pingcap/parser/types"
+
+	driver "github.com/Sterrenhemel/ddl2struct/pkg/parser_driver"
+	tidbtypes "github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// compareOps are the comparison operators of checks, as dbtypes.CheckValue.Compare takes them.
+var compareOps = map[opcode.Op]string{
+	opcode.EQ:     "=",
+	opcode.NE:     "<>",
+	opcode.LT:     "<",
+	opcode.LE:     "<=",
+	opcode.GT:     ">",
+	opcode.GE:     ">=",
+	opcode.NullEQ: "<=>",
+}
+
+// Check is a CHECK constraint of a table or a column.
+type Check struct {
+	Name     string
+	Expr     string // the condition, as SQL text
+	Enforced bool
+	// Cond is the condition as a Go expression of type dbtypes.Truth on
+	// receiver t. It is empty when the check is not enforced or the
+	// condition cannot be translated.
+	Cond string
+
+	expr ast.ExprNode
+}
+
+type Checks []*Check
+
+// Find returns the position of the check named name, or -1.
+func (checks Checks) Find(name string) int {
+	for i, check := range checks {
+		if strings.EqualFold(check.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func newCheck(name string, expr ast.ExprNode, enforced bool) *Check {
+	return &Check{
+		Name:     name,
+		Expr:     restoreCond(expr),
+		Enforced: enforced,
+		expr:     expr,
+	}
+}
+
+// restoreCond returns a condition as SQL text, spaced as people write it
+// since it ends up in validation errors.
+func restoreCond(expr ast.ExprNode) string {
+	var sb strings.Builder
+	flags := format.RestoreStringSingleQuotes | format.RestoreKeyWordUppercase | format.RestoreSpacesAroundBinaryOperation
+	if err := expr.Restore(format.NewRestoreCtx(flags, &sb)); err != nil {
+		return ""
+	}
+	return sb.String()
+}
+
+// addCheck registers check on the table, naming it <table>_chk_<n> as MySQL
+// does when the DDL leaves the name out.
+func (table *Table) addCheck(check *Check) error {
+	if check.Name == "" {
+		check.Name = fmt.Sprintf("%s_chk_%d", table.TableName, table.nextCheckNumber())
+	}
+	if table.Checks.Find(check.Name) >= 0 {
+		return errors.Errorf("duplicate check constraint name :%s", check.Name)
+	}
+	table.Checks = append(table.Checks, check)
+	return nil
+}
+
+func (table *Table) nextCheckNumber() int {
+	prefix := strings.ToLower(table.TableName + "_chk_")
+	n := 0
+	for _, check := range table.Checks {
+		if !strings.HasPrefix(strings.ToLower(check.Name), prefix) {
+			continue
+		}
+		if i, err := strconv.Atoi(check.Name[len(prefix):]); err == nil && i > n {
+			n = i
+		}
+	}
+	return n + 1
+}
+
+// addColumnChecks adds the checks declared inline by CHECK column options.
+func (parser *DDLParser) addColumnChecks(table *Table, col *ast.ColumnDef) error {
+	for _, option := range col.Options {
+		if option.Tp != ast.ColumnOptionCheck {
+			continue
+		}
+		if err := table.addCheck(newCheck("", option.Expr, option.Enforced)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (table *Table) dropCheck(name string) error {
+	pos := table.Checks.Find(name)
+	if pos < 0 {
+		return errors.Errorf("check constraint %s doesn't exist", name)
+	}
+	table.Checks = append(table.Checks[:pos], table.Checks[pos+1:]...)
+	return nil
+}
+
+func (table *Table) enforceCheck(name string, enforced bool) error {
+	pos := table.Checks.Find(name)
+	if pos < 0 {
+		return errors.Errorf("check constraint %s doesn't exist", name)
+	}
+	table.Checks[pos].Enforced = enforced
+	return nil
+}
+
+// resolveChecks translates the enforced checks of table to Go. Checks that
+// cannot be translated are reported as warnings and left out of validation.
+func (parser *DDLParser) resolveChecks(table *Table) {
+	for _, check := range table.Checks {
+		check.Cond = ""
+		if !check.Enforced {
+			continue
+		}
+		cond, err := checkCond(table, check.expr)
+		if err != nil {
+			parser.warnf("table %s: check constraint %s is not validated: %v", table.TableName, check.Name, err)
+			continue
+		}
+		check.Cond = cond
+	}
+}
+
+// checkCond translates a condition made of comparisons, IN, BETWEEN, LIKE,
+// IS NULL, AND, OR and NOT over the columns of table and constants into a Go
+// expression of type dbtypes.Truth.
+func checkCond(table *Table, expr ast.ExprNode) (string, error) {
+	switch expr := expr.(type) {
+	case *ast.ParenthesesExpr:
+		return checkCond(table, expr.Expr)
+	case *ast.BinaryOperationExpr:
+		switch expr.Op {
+		case opcode.LogicAnd, opcode.LogicOr:
+			left, err := checkCond(table, expr.L)
+			if err != nil {
+				return "", err
+			}
+			right, err := checkCond(table, expr.R)
+			if err != nil {
+				return "", err
+			}
+			method := "And"
+			if expr.Op == opcode.LogicOr {
+				method = "Or"
+			}
+			return fmt.Sprintf("%s.%s(%s)", left, method, right), nil
+		}
+		if op, ok := compareOps[expr.Op]; ok {
+			left, err := checkOperand(table, expr.L, "")
+			if err != nil {
+				return "", err
+			}
+			right, err := checkOperand(table, expr.R, "")
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s.Compare(%q, %s)", left, op, right), nil
+		}
+	case *ast.UnaryOperationExpr:
+		if expr.Op == opcode.Not {
+			cond, err := checkCond(table, expr.V)
+			if err != nil {
+				return "", err
+			}
+			return cond + ".Not()", nil
+		}
+	case *ast.IsNullExpr:
+		operand, err := checkOperand(table, expr.Expr, "")
+		if err != nil {
+			return "", err
+		}
+		return negate(operand+".IsNull()", expr.Not), nil
+	case *ast.PatternInExpr:
+		if expr.Sel != nil {
+			break
+		}
+		operands := make([]string, 0, len(expr.List)+1)
+		for _, e := range append([]ast.ExprNode{expr.Expr}, expr.List...) {
+			operand, err := checkOperand(table, e, "")
+			if err != nil {
+				return "", err
+			}
+			operands = append(operands, operand)
+		}
+		return negate(fmt.Sprintf("%s.In(%s)", operands[0], strings.Join(operands[1:], ", ")), expr.Not), nil
+	case *ast.BetweenExpr:
+		operands := make([]string, 0, 3)
+		for _, e := range []ast.ExprNode{expr.Expr, expr.Left, expr.Right} {
+			operand, err := checkOperand(table, e, "")
+			if err != nil {
+				return "", err
+			}
+			operands = append(operands, operand)
+		}
+		return negate(fmt.Sprintf("%s.Between(%s, %s)", operands[0], operands[1], operands[2]), expr.Not), nil
+	case *ast.PatternLikeExpr:
+		operand, err := checkOperand(table, expr.Expr, "")
+		if err != nil {
+			return "", err
+		}
+		pattern, err := checkOperand(table, expr.Pattern, "")
+		if err != nil {
+			return "", err
+		}
+		escape := strconv.QuoteRuneToASCII(rune(expr.Escape))
+		return negate(fmt.Sprintf("%s.Like(%s, %s)", operand, pattern, escape), expr.Not), nil
+	case *ast.ColumnNameExpr, *driver.ValueExpr:
+		operand, err := checkOperand(table, expr, "")
+		if err != nil {
+			return "", err
+		}
+		return operand + ".Truth()", nil
+	}
+	return "", errors.Errorf("unsupported expression %s", restoreNode(expr))
+}
+
+func negate(cond string, not bool) string {
+	if not {
+		return cond + ".Not()"
+	}
+	return cond
+}
+
+// checkOperand translates a column or a constant into a Go expression of type
+// dbtypes.CheckValue. collation is set by an enclosing COLLATE clause.
+func checkOperand(table *Table, expr ast.ExprNode, collation string) (string, error) {
+	switch expr := expr.(type) {
+	case *ast.ParenthesesExpr:
+		return checkOperand(table, expr.Expr, collation)
+	case *ast.SetCollationExpr:
+		return checkOperand(table, expr.Expr, effectiveCollation(&types.FieldType{Collate: expr.Collate}))
+	case *ast.ColumnNameExpr:
+		name := expr.Name.Name.String()
+		pos := table.Columns.Find(name)
+		if pos < 0 {
+			return "", errors.Errorf("unknown column %s", name)
+		}
+		column := table.Columns[pos]
+		if collation == "" && column.FieldType.EvalType() == types.ETString {
+			collation = effectiveCollation(column.FieldType)
+		}
+		return fmt.Sprintf("dbtypes.CheckValueOf(t.%s, %q)", column.FieldName, collation), nil
+	}
+	d, ok, err := evalConstant(expr)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		if value, ok := checkConstant(d); ok {
+			return fmt.Sprintf("dbtypes.CheckValueOf(%s, %q)", value, collation), nil
+		}
+	}
+	return "", errors.Errorf("unsupported operand %s", restoreNode(expr))
+}
+
+// checkConstant renders a constant as a Go value CheckValueOf takes.
+func checkConstant(d tidbtypes.Datum) (string, bool) {
+	switch d.Kind() {
+	case tidbtypes.KindNull:
+		return "nil", true
+	case tidbtypes.KindInt64:
+		return fmt.Sprintf("int64(%d)", d.GetInt64()), true
+	case tidbtypes.KindUint64:
+		return fmt.Sprintf("uint64(%d)", d.GetUint64()), true
+	case tidbtypes.KindFloat32, tidbtypes.KindFloat64:
+		return fmt.Sprintf("float64(%s)", strconv.FormatFloat(d.GetFloat64(), 'g', -1, 64)), true
+	case tidbtypes.KindMysqlDecimal:
+		return fmt.Sprintf("dbtypes.MustParseDecimal(%q)", d.GetMysqlDecimal().String()), true
+	case tidbtypes.KindString:
+		return strconv.Quote(d.GetString()), true
+	}
+	return "", false
+}
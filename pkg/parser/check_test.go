@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/dbtypes"
+)
+
+const checkTable = `CREATE TABLE t (
+	age int,
+	name varchar(32),
+	code varchar(8) COLLATE utf8mb4_bin,
+	email varchar(64) NULL,
+	b blob,
+	%s
+)`
+
+func TestCheckCond(t *testing.T) {
+	for _, test := range []struct {
+		check string
+		cond  string // empty when the check is not translated
+		warn  string // the reason of the warning when not translated
+	}{
+		{"CHECK (age >= 18)", `dbtypes.CheckValueOf(t.Age, "").Compare(">=", dbtypes.CheckValueOf(int64(18), ""))`, ""},
+		{"CHECK (age <> 0)", `dbtypes.CheckValueOf(t.Age, "").Compare("<>", dbtypes.CheckValueOf(int64(0), ""))`, ""},
+		{"CHECK (age != 0)", `dbtypes.CheckValueOf(t.Age, "").Compare("<>", dbtypes.CheckValueOf(int64(0), ""))`, ""},
+		{"CHECK (1.5 < age)", `dbtypes.CheckValueOf(dbtypes.MustParseDecimal("1.5"), "").Compare("<", dbtypes.CheckValueOf(t.Age, ""))`, ""},
+		{"CHECK (age <=> NULL)", `dbtypes.CheckValueOf(t.Age, "").Compare("<=>", dbtypes.CheckValueOf(nil, ""))`, ""},
+		{"CHECK (age)", `dbtypes.CheckValueOf(t.Age, "").Truth()`, ""},
+		// String columns compare with their collation, constants take it.
+		{"CHECK (name = 'Bob')", `dbtypes.CheckValueOf(t.Name, "utf8mb4_general_ci").Compare("=", dbtypes.CheckValueOf("Bob", ""))`, ""},
+		{"CHECK (code = 'A')", `dbtypes.CheckValueOf(t.Code, "utf8mb4_bin").Compare("=", dbtypes.CheckValueOf("A", ""))`, ""},
+		{"CHECK (name COLLATE utf8mb4_bin = 'bob')", `dbtypes.CheckValueOf(t.Name, "utf8mb4_bin").Compare("=", dbtypes.CheckValueOf("bob", ""))`, ""},
+		{"CHECK (email IS NULL OR email LIKE '%@%')",
+			`dbtypes.CheckValueOf(t.Email, "utf8mb4_general_ci").IsNull().Or(dbtypes.CheckValueOf(t.Email, "utf8mb4_general_ci").Like(dbtypes.CheckValueOf("%@%", ""), '\\'))`, ""},
+		{"CHECK (email IS NOT NULL)", `dbtypes.CheckValueOf(t.Email, "utf8mb4_general_ci").IsNull().Not()`, ""},
+		{"CHECK (age BETWEEN 1 AND 150 AND name <> '')",
+			`dbtypes.CheckValueOf(t.Age, "").Between(dbtypes.CheckValueOf(int64(1), ""), dbtypes.CheckValueOf(int64(150), "")).And(dbtypes.CheckValueOf(t.Name, "utf8mb4_general_ci").Compare("<>", dbtypes.CheckValueOf("", "")))`, ""},
+		{"CHECK (code NOT IN ('x', 'y'))", `dbtypes.CheckValueOf(t.Code, "utf8mb4_bin").In(dbtypes.CheckValueOf("x", ""), dbtypes.CheckValueOf("y", "")).Not()`, ""},
+		{"CHECK (NOT (age < 0))", `dbtypes.CheckValueOf(t.Age, "").Compare("<", dbtypes.CheckValueOf(int64(0), "")).Not()`, ""},
+		// Skipped checks.
+		{"CHECK (age + 1 > 2)", "", "unsupported operand age+1"},
+		{"CHECK (LENGTH(name) > 3)", "", "unsupported operand LENGTH(name)"},
+		{"CHECK (nope > 1)", "", "unknown column nope"},
+		{"CHECK (b = x'00')", "", "unsupported operand x'00'"},
+		{"CHECK (age > 0) NOT ENFORCED", "", ""},
+	} {
+		parser := parseSQL(t, nil, strings.Replace(checkTable, "%s", test.check, 1))
+		table := tableOf(t, parser, "t")
+		if len(table.Checks) != 1 {
+			t.Fatalf("%s: got %d checks", test.check, len(table.Checks))
+		}
+		if got := table.Checks[0].Cond; got != test.cond {
+			t.Errorf("%s:\ngot  %s\nwant %s", test.check, got, test.cond)
+		}
+		if test.warn == "" {
+			if len(parser.Warnings) > 0 {
+				t.Errorf("%s: unexpected warnings %v", test.check, parser.Warnings)
+			}
+		} else if len(parser.Warnings) != 1 || !strings.HasSuffix(parser.Warnings[0], "is not validated: "+test.warn) {
+			t.Errorf("%s: got warnings %v, want %s", test.check, parser.Warnings, test.warn)
+		}
+		if table.HasValidate() != (test.cond != "") {
+			t.Errorf("%s: got HasValidate %v", test.check, table.HasValidate())
+		}
+	}
+}
+
+func TestCheckCollation(t *testing.T) {
+	table := tableOf(t, parseSQL(t, nil, strings.Replace(checkTable, "%s", "CHECK (age > 0)", 1)), "t")
+	ci := effectiveCollation(columnOf(t, table, "name").FieldType)
+	bin := effectiveCollation(columnOf(t, table, "code").FieldType)
+	for _, test := range []struct {
+		collation string
+		op        string
+		a, b      string
+		want      dbtypes.Truth
+	}{
+		{ci, "=", "bob", "BOB", dbtypes.TruthTrue},
+		{bin, "=", "bob", "BOB", dbtypes.TruthFalse},
+		{ci, "=", "bob", "bob  ", dbtypes.TruthTrue},
+		{bin, "=", "bob", "bob  ", dbtypes.TruthTrue},
+		{ci, "<", "apple", "Banana", dbtypes.TruthTrue},
+		{bin, "<", "apple", "Banana", dbtypes.TruthFalse},
+		{ci, "LIKE", "Alice@Example.com", "%@example.com", dbtypes.TruthTrue},
+		{bin, "LIKE", "Alice@Example.com", "%@example.com", dbtypes.TruthFalse},
+	} {
+		v, other := dbtypes.CheckValueOf(test.a, test.collation), dbtypes.CheckValueOf(test.b, "")
+		var got dbtypes.Truth
+		if test.op == "LIKE" {
+			got = v.Like(other, '\\')
+		} else {
+			got = v.Compare(test.op, other)
+		}
+		if got != test.want {
+			t.Errorf("%q %s %q with %s: got %v, want %v", test.a, test.op, test.b, test.collation, got, test.want)
+		}
+	}
+}
+
+func TestCheckNull(t *testing.T) {
+	null := dbtypes.CheckValueOf((*string)(nil), "utf8mb4_general_ci")
+	email := dbtypes.CheckValueOf("a@b.c", "utf8mb4_general_ci")
+	one := dbtypes.CheckValueOf(int64(1), "")
+	for _, test := range []struct {
+		name string
+		got  dbtypes.Truth
+		want dbtypes.Truth
+	}{
+		{"NULL = 1", null.Compare("=", one), dbtypes.TruthUnknown},
+		{"NULL <> 1", null.Compare("<>", one), dbtypes.TruthUnknown},
+		{"NULL <=> NULL", null.Compare("<=>", dbtypes.CheckValueOf(nil, "")), dbtypes.TruthTrue},
+		{"NULL <=> 1", null.Compare("<=>", one), dbtypes.TruthFalse},
+		{"NULL LIKE '%'", null.Like(dbtypes.CheckValueOf("%", ""), '\\'), dbtypes.TruthUnknown},
+		{"NULL IS NULL OR NULL LIKE '%@%'", null.IsNull().Or(null.Like(dbtypes.CheckValueOf("%@%", ""), '\\')), dbtypes.TruthTrue},
+		{"'a@b.c' IS NULL OR 'a@b.c' LIKE '%@%'", email.IsNull().Or(email.Like(dbtypes.CheckValueOf("%@%", ""), '\\')), dbtypes.TruthTrue},
+		{"1 IN (2, NULL)", one.In(dbtypes.CheckValueOf(int64(2), ""), dbtypes.CheckValueOf(nil, "")), dbtypes.TruthUnknown},
+		{"1 IN (1, NULL)", one.In(one, dbtypes.CheckValueOf(nil, "")), dbtypes.TruthTrue},
+		{"1 NOT IN (2, NULL)", one.In(dbtypes.CheckValueOf(int64(2), ""), dbtypes.CheckValueOf(nil, "")).Not(), dbtypes.TruthUnknown},
+		{"NULL BETWEEN 0 AND 2", null.Between(dbtypes.CheckValueOf(int64(0), ""), dbtypes.CheckValueOf(int64(2), "")), dbtypes.TruthUnknown},
+		{"NULL AND FALSE", dbtypes.TruthUnknown.And(dbtypes.TruthFalse), dbtypes.TruthFalse},
+		{"NULL OR TRUE", dbtypes.TruthUnknown.Or(dbtypes.TruthTrue), dbtypes.TruthTrue},
+		{"NOT NULL", dbtypes.TruthUnknown.Not(), dbtypes.TruthUnknown},
+		{"0", dbtypes.CheckValueOf(int64(0), "").Truth(), dbtypes.TruthFalse},
+		{"NULL", null.Truth(), dbtypes.TruthUnknown},
+	} {
+		if test.got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, test.got, test.want)
+		}
+	}
+	// Only false conditions violate a check, unknown ones pass.
+	if dbtypes.TruthUnknown.IsFalse() || !dbtypes.TruthFalse.IsFalse() {
+		t.Errorf("IsFalse: unknown must pass and false must fail")
+	}
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/types"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/dbtypes"
+	tidbtypes "github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// defaultCollation is the collation of string columns that do not declare one,
+// case-insensitive as with MySQL's utf8mb4 default.
+const defaultCollation = "utf8mb4_general_ci"
+
+// effectiveCollation returns the collation values of fieldType are compared
+// with, among the collations the vendored collate package implements.
+func effectiveCollation(fieldType *types.FieldType) string {
+	if fieldType.Charset == charset.CharsetBin || fieldType.Collate == charset.CollationBin {
+		return charset.CollationBin
+	}
+	name := strings.ToLower(fieldType.Collate)
+	switch {
+	case name == "":
+		if mysql.HasBinaryFlag(fieldType.Flag) {
+			return "utf8mb4_bin"
+		}
+		return defaultCollation
+	case strings.HasSuffix(name, "_bin"):
+		return "utf8mb4_bin"
+	}
+	return defaultCollation
+}
+
+// withEffectiveCollation returns a copy of fieldType whose collation is the
+// one values are compared with, see effectiveCollation.
+func withEffectiveCollation(fieldType *types.FieldType) *types.FieldType {
+	ft := fieldType.Clone()
+	ft.Collate = effectiveCollation(fieldType)
+	return ft
+}
+
+// withElementNames returns d, a string naming elements of the ENUM or SET
+// column of fieldType, as the elements themselves, matched with the
+// effective collation. Datum.ConvertTo matches them with the collation only
+// when the new collations are enabled process-wide. Other values are
+// returned as they are, for ConvertTo to report.
+func withElementNames(d tidbtypes.Datum, fieldType *types.FieldType) tidbtypes.Datum {
+	if d.Kind() != tidbtypes.KindString {
+		return d
+	}
+	collation := effectiveCollation(fieldType)
+	switch fieldType.Tp {
+	case mysql.TypeEnum:
+		if name, err := dbtypes.ParseEnum(fieldType.Elems, collation, d.GetString()); err == nil {
+			d.SetString(name, d.Collation())
+		}
+	case mysql.TypeSet:
+		if bits, err := dbtypes.ParseSet(fieldType.Elems, collation, d.GetString()); err == nil {
+			if name, err := dbtypes.SetName(fieldType.Elems, bits); err == nil {
+				d.SetString(name, d.Collation())
+			}
+		}
+	}
+	return d
+}
@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestElementDefaults(t *testing.T) {
+	for _, test := range []struct {
+		column string
+		want   string // Go literal of the default; empty when it is an error
+	}{
+		{"size ENUM('Small','Large') DEFAULT 'small'", "Small"},
+		{"size ENUM('Small','Large') DEFAULT 'LARGE '", "Large"},
+		{"size ENUM('Small','Large') COLLATE utf8mb4_bin DEFAULT 'small'", ""},
+		{"size SET('Red','Blue') DEFAULT 'blue,red'", "3"},
+	} {
+		parser := New("test.sql", "", "test")
+		err := parser.ParseInputs(Input{File: "test.sql", SQL: "CREATE TABLE t (" + test.column + ")"})
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("%s: got no error", test.column)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.column, err)
+			continue
+		}
+		if got := columnOf(t, tableOf(t, parser, "t"), "size").DefaultVal; !strings.Contains(got, test.want) {
+			t.Errorf("%s: got default %s, want %s", test.column, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package parser
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/config"
+)
+
+// SetConfig applies the table and column settings of cfg: names, types,
+// nullable strategies, tags, output files and packages, and the tables to
+// generate. The project settings are left to the caller, as they compete with
+// command line flags. The settings are checked here, since their values are
+// those the parser takes.
+func (parser *DDLParser) SetConfig(cfg *config.Config) error {
+	for name, table := range cfg.Tables {
+		if table == nil {
+			continue
+		}
+		keyPath := []string{"tables", name}
+		if _, err := ParseNullableStrategy(table.Nullable); err != nil {
+			return cfg.Errorf(append(keyPath, "nullable"), "%v", err)
+		}
+		for key, goType := range table.Types {
+			if _, err := parser.qualifyType(goType); err != nil {
+				return cfg.Errorf(append(keyPath, "types", key), "%v", err)
+			}
+		}
+		for column, columnConfig := range table.Columns {
+			if err := parser.checkColumnConfig(cfg, append(keyPath, "columns", column), columnConfig); err != nil {
+				return err
+			}
+		}
+	}
+	for key, columnConfig := range cfg.Columns {
+		if err := parser.checkColumnConfig(cfg, []string{"columns", key}, columnConfig); err != nil {
+			return err
+		}
+	}
+	parser.config = cfg
+	return nil
+}
+
+// CheckProjectConfig checks the project settings of cfg that the parser takes,
+// reporting a bad value at its line. They are checked before they compete
+// with the command line flags, which report their own errors.
+func CheckProjectConfig(cfg *config.Config) error {
+	scratch := New("", "", "")
+	for _, setting := range []struct {
+		key   string
+		value string
+		check func(string) error
+	}{
+		{"nullable", cfg.Nullable, func(name string) error {
+			_, err := ParseNullableStrategy(name)
+			return err
+		}},
+		{"null_type", cfg.NullType, scratch.SetGenericNullType},
+		{"tags", cfg.Tags, scratch.SetTags},
+		{"collisions", cfg.Collisions, func(name string) error {
+			_, err := ParseCollisionStrategy(name)
+			return err
+		}},
+	} {
+		if setting.value == "" {
+			continue
+		}
+		if err := setting.check(setting.value); err != nil {
+			return cfg.Errorf([]string{setting.key}, "%v", err)
+		}
+	}
+	keys := make([]string, 0, len(cfg.Types))
+	for key := range cfg.Types {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, err := scratch.qualifyType(cfg.Types[key]); err != nil {
+			return cfg.Errorf([]string{"types", key}, "%v", err)
+		}
+	}
+	return nil
+}
+
+func (parser *DDLParser) checkColumnConfig(cfg *config.Config, keyPath []string, column *config.Column) error {
+	if column == nil {
+		return nil
+	}
+	if _, err := ParseNullableStrategy(column.Nullable); err != nil {
+		return cfg.Errorf(append(keyPath, "nullable"), "%v", err)
+	}
+	if column.Type != "" {
+		if _, err := parser.qualifyType(column.Type); err != nil {
+			return cfg.Errorf(append(keyPath, "type"), "%v", err)
+		}
+	}
+	return nil
+}
+
+// configOutput returns the output file of a table created with the output
+// file fileName, and its package, as set by the config. An output file is
+// relative to the output directory, a package without output file puts the
+// table in the subdirectory of the package.
+func (parser *DDLParser) configOutput(tableName, fileName string) (string, string) {
+	table := parser.config.Table(tableName)
+	dir := path.Dir(fileName)
+	if table.Output != "" {
+		fileName = table.Output
+		if !strings.HasSuffix(fileName, ".go") {
+			fileName += ".go"
+		}
+		if !path.IsAbs(fileName) {
+			fileName = path.Join(dir, fileName)
+		}
+	} else if table.Package != "" {
+		fileName = path.Join(dir, table.Package, path.Base(fileName))
+	}
+	return fileName, table.Package
+}
+
+// excludeTables forgets the tables the config does not include.
+func (parser *DDLParser) excludeTables() {
+	for fileName, tables := range parser.FileTables {
+		for tableName := range tables {
+			if !parser.config.Included(tableName) {
+				parser.removeTable(fileName, tableName)
+			}
+		}
+	}
+}
+
+// checkPackages fails when tables of different packages share an output file.
+func (parser *DDLParser) checkPackages() error {
+	for fileName, tables := range parser.FileTables {
+		names := make([]string, 0, len(tables))
+		for tableName := range tables {
+			names = append(names, tableName)
+		}
+		sort.Strings(names)
+		for _, tableName := range names[1:] {
+			if tables[tableName].Package != tables[names[0]].Package {
+				return errors.Errorf("tables %s and %s are generated in %s with different packages", names[0], tableName, fileName)
+			}
+		}
+	}
+	return nil
+}
+
+// FilePackage returns the package of the output file fileName: that of its
+// tables, or the package of the parser.
+func (parser *DDLParser) FilePackage(fileName string) string {
+	for _, table := range parser.FileTables[fileName] {
+		if table.Package != "" {
+			return table.Package
+		}
+	}
+	return parser.packageName
+}
+
+// resolveNames sets the Go names of table and of its columns, as set by the
+// config or derived from the SQL names, declaring the struct in the name space
+// of its package and recording how it was named in Renamings. It returns the
+// name space of the struct.
+func (parser *DDLParser) resolveNames(table *Table, pkg nameSpace) (nameSpace, error) {
+	name, rule := parser.structName(table.TableName)
+	var err error
+	table.StructName, err = parser.declareGroup(pkg, name, func(name string) nameSpace {
+		space := nameSpace{name: "table " + table.TableName}
+		if parser.Constructors {
+			space["New"+name] = "constructor of table " + table.TableName
+		}
+		return space
+	})
+	if err != nil {
+		return nil, err
+	}
+	if table.StructName != name {
+		rule += ", numbered as " + name + " is taken"
+	}
+	parser.Renamings = append(parser.Renamings, Renaming{TableName: table.TableName, StructName: table.StructName, Rule: rule})
+	fields := parser.structSpace(table)
+	for i := range table.Columns {
+		column := &table.Columns[i]
+		name := parser.config.Column(table.TableName, column.Name).Name
+		if name == "" {
+			name = parser.Naming.Exported(column.Name)
+		}
+		if column.FieldName, err = parser.declare(fields, name, "column "+column.Name+" of table "+table.TableName); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// applyColumnConfig sets the Go type, nullable strategy and tags the config
+// gives column. It reports whether the type was overridden, by the config or
+// a @type annotation, in which case no enum type is generated.
+func (parser *DDLParser) applyColumnConfig(table *Table, column *Column) (bool, NullableStrategy) {
+	tableConfig := parser.config.Table(table.TableName)
+	columnConfig := parser.config.Column(table.TableName, column.Name)
+	column.Tags = columnConfig.Tags
+
+	overridden := column.Annotations["type"] != ""
+	goType := columnConfig.Type
+	if goType == "" && !overridden {
+		key := typeKey(column.FieldType)
+		for typeName, tableType := range tableConfig.Types {
+			if strings.EqualFold(typeName, key) {
+				goType = tableType
+			}
+		}
+	}
+	if columnConfig.Type != "" && column.FieldType.Tp == mysql.TypeJSON {
+		// As with a @type annotation.
+		column.Serializer = "json"
+	}
+	if goType != "" {
+		// Checked by SetConfig.
+		column.BaseType, _ = parser.qualifyType(goType)
+		overridden = true
+	}
+
+	strategy := parser.Nullable
+	for _, name := range []string{tableConfig.Nullable, columnConfig.Nullable} {
+		if name != "" {
+			strategy = NullableStrategy(name)
+		}
+	}
+	return overridden, strategy
+}
+
+// withTags reports whether the fields of table have the tags of the tag
+// families.
+func (parser *DDLParser) withTags(table *Table) bool {
+	if parser.config == nil {
+		return true
+	}
+	if withTags := parser.config.Table(table.TableName).WithTags; withTags != nil {
+		return *withTags
+	}
+	if parser.config.WithTags != nil {
+		return *parser.config.WithTags
+	}
+	return true
+}
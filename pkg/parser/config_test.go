@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/config"
+)
+
+// loadConfig writes content to a config file named name and loads it.
+func loadConfig(t *testing.T, name, content string) (*config.Config, string) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(file)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return cfg, file
+}
+
+func TestCheckProjectConfig(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		content string
+		err     string // after the file name; empty when the config is valid
+	}{
+		{"ok.yaml", "nullable: sql\ntypes:\n  int: int64\ntags: json:camel,gorm\ncollisions: suffix\n", ""},
+		{"nullable.yaml", "package: models\nnullable: bogus\n", ":2: nullable: unknown nullable strategy :bogus"},
+		{"types.yaml", "package: models\ntypes:\n  decimal: float64\n  int: bad.\n", ":4: types.int: type must look like import/path.Name :bad."},
+		{"null_type.yaml", "null_type: Option\n", ":1: null_type: generic null type must look like import/path.Name :Option"},
+		{"tags.yaml", "\n\ntags: json,foo\n", ":3: tags: unknown tag family :foo"},
+		{"collisions.yaml", "collisions: rename\n", ":1: collisions: unknown collision strategy :rename"},
+		{"nullable.toml", "package = \"models\"\nnullable = \"bogus\"\n", ":2: nullable: unknown nullable strategy :bogus"},
+		{"types.toml", "package = \"models\"\n\n[types]\nint = \"bad.\"\n", ":4: types.int: type must look like import/path.Name :bad."},
+		{"tags.toml", "tags = \"json,foo\"\n", ":1: tags: unknown tag family :foo"},
+	} {
+		cfg, file := loadConfig(t, test.name, test.content)
+		err := CheckProjectConfig(cfg)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.HasPrefix(err.Error(), file+test.err) {
+			t.Errorf("%s: got %v, want %s%s", test.name, err, file, test.err)
+		}
+	}
+}
+
+func TestSetConfigErrors(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"table.yaml", "tables:\n  users:\n    nullable: bogus\n", ":3: tables.users.nullable: unknown nullable strategy :bogus"},
+		{"column.yaml", "columns:\n  \"*.id\":\n    type: bad.\n", ":3: columns.*.id.type: type must look like import/path.Name :bad."},
+		{"table.toml", "[tables.users]\nnullable = \"bogus\"\n", ":2: tables.users.nullable: unknown nullable strategy :bogus"},
+	} {
+		cfg, file := loadConfig(t, test.name, test.content)
+		err := New("", "", "test").SetConfig(cfg)
+		if err == nil || !strings.HasPrefix(err.Error(), file+test.err) {
+			t.Errorf("%s: got %v, want %s%s", test.name, err, file, test.err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	tidbtypes "github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// fspUnits holds, for each fractional seconds precision, the duration
+// CURRENT_TIMESTAMP is truncated to.
+var fspUnits = [...]string{
+	"time.Second",
+	"100 * time.Millisecond",
+	"10 * time.Millisecond",
+	"time.Millisecond",
+	"100 * time.Microsecond",
+	"10 * time.Microsecond",
+	"time.Microsecond",
+}
+
+// defaultExpr returns the Go expression a constructor assigns to the field of
+// column, or "" when the field is left zero: no DEFAULT, AUTO_INCREMENT, a
+// generated column, or a default the generator cannot express for the field type.
+func (parser *DDLParser) defaultExpr(column Column) string {
+	if column.AutoIncrement || column.Generated != "" {
+		return ""
+	}
+	value := column.DefaultVal
+	if column.DefaultNow {
+		fsp, err := tidbtypes.CheckFsp(column.FieldType.Decimal)
+		if err != nil {
+			return ""
+		}
+		switch column.BaseType {
+		case "time.Time":
+			value = "time.Now().Truncate(" + fspUnits[fsp] + ")"
+		case "dbtypes.MySQLTime":
+			value = fmt.Sprintf("dbtypes.MySQLTimeNow(%d)", fsp)
+		default:
+			return ""
+		}
+	}
+	if value == "" || column.Type == column.BaseType {
+		return value
+	}
+	switch {
+	case strings.HasPrefix(column.Type, "*"):
+		return fmt.Sprintf("func(v %s) %s { return &v }(%s)", column.BaseType, column.Type, value)
+	case strings.HasPrefix(column.Type, parser.genericNullType+"["):
+		if parser.genericNullValue == nil {
+			return ""
+		}
+		return parser.genericNullValue(column.Type, column.BaseType, value)
+	case column.Type == sqlNullTypes[column.BaseType]:
+		// sql.NullInt64{Int64: v, Valid: true}, dbtypes.NullDecimal{Decimal: v, Valid: true}
+		field := strings.TrimPrefix(column.Type[strings.Index(column.Type, ".")+1:], "Null")
+		return fmt.Sprintf("%s{%s: %s, Valid: true}", column.Type, field, value)
+	}
+	return ""
+}
@@ -0,0 +1,34 @@
+package parser
+
+import "testing"
+
+func TestNullableDefaultExpr(t *testing.T) {
+	for _, test := range []struct {
+		nullable NullableStrategy
+		nullType string // generic type, DefaultGenericNullType when empty
+		expr     string
+	}{
+		{NullableNone, "", `5`},
+		{NullablePointer, "", `func(v int32) *int32 { return &v }(5)`},
+		{NullableSQL, "", `sql.NullInt32{Int32: 5, Valid: true}`},
+		{NullableGeneric, "", `sql.Null[int32]{V: 5, Valid: true}`},
+		{NullableGeneric, "github.com/samber/mo.Option", `mo.Some[int32](5)`},
+		// Unknown generic types leave the field zero.
+		{NullableGeneric, "example.com/opt.Maybe", ``},
+		// A package named sql other than database/sql.
+		{NullableGeneric, "example.com/sql.Null", ``},
+	} {
+		parser := New("test.sql", "", "test")
+		parser.Constructors = true
+		parser.Nullable = test.nullable
+		if test.nullType != "" {
+			if err := parser.SetGenericNullType(test.nullType); err != nil {
+				t.Fatal(err)
+			}
+		}
+		table := tableOf(t, parseSQL(t, parser, "CREATE TABLE t (n int DEFAULT 5)"), "t")
+		if got := columnOf(t, table, "n").DefaultExpr; got != test.expr {
+			t.Errorf("%s %s: got %s, want %s", test.nullable, test.nullType, got, test.expr)
+		}
+	}
+}
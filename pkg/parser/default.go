@@ -0,0 +1,185 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
+
+	driver "github.com/Sterrenhemel/ddl2struct/pkg/parser_driver"
+	"github.com/Sterrenhemel/ddl2struct/pkg/stmtctx"
+	tidbtypes "github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// currentTimestampFuncs are the functions MySQL accepts as DEFAULT and ON
+// UPDATE of temporal columns.
+var currentTimestampFuncs = map[string]bool{
+	ast.CurrentTimestamp: true,
+	ast.Now:              true,
+	ast.LocalTime:        true,
+	ast.LocalTimestamp:   true,
+}
+
+// defaultStmtCtx converts DEFAULT values the way a strict sql_mode does, but
+// keeps the zero dates found in legacy schemas.
+func defaultStmtCtx() *stmtctx.StatementContext {
+	return &stmtctx.StatementContext{
+		TimeZone:         time.UTC,
+		IgnoreZeroInDate: true,
+	}
+}
+
+// isCurrentTimestamp reports whether expr is CURRENT_TIMESTAMP or one of its synonyms.
+func isCurrentTimestamp(expr ast.ExprNode) bool {
+	call, ok := expr.(*ast.FuncCallExpr)
+	return ok && currentTimestampFuncs[call.FnName.L]
+}
+
+// evalDefault evaluates the DEFAULT expression of column, converted to the
+// column type. Expressions that are not constants are kept as SQL text only.
+func (parser *DDLParser) evalDefault(column *Column, expr ast.ExprNode) error {
+	column.DefaultSQL = restoreNode(expr)
+	column.defaultDatum = nil
+	if isCurrentTimestamp(expr) {
+		column.DefaultNow = true
+		return nil
+	}
+	d, ok, err := evalConstant(expr)
+	if err != nil || !ok {
+		return err
+	}
+	if d.IsNull() {
+		return nil
+	}
+	sc := defaultStmtCtx()
+	// MySQL rounds extra fractional digits of a DEFAULT with a note only.
+	switch column.FieldType.Tp {
+	case mysql.TypeNewDecimal, mysql.TypeFloat, mysql.TypeDouble:
+		sc.TruncateAsWarning = true
+	}
+	d = withElementNames(d, column.FieldType)
+	converted, err := d.ConvertTo(sc, withEffectiveCollation(column.FieldType))
+	if err != nil {
+		return errors.Annotatef(err, "invalid default value for %s", column.Name)
+	}
+	column.defaultDatum = &converted
+	return nil
+}
+
+// resolveDefault records the DEFAULT of column as a Go literal of its base type.
+func resolveDefault(column *Column) error {
+	column.DefaultVal = ""
+	if column.defaultDatum == nil {
+		return nil
+	}
+	var err error
+	column.DefaultVal, err = goLiteral(defaultStmtCtx(), *column.defaultDatum, column.BaseType)
+	return errors.Annotatef(err, "invalid default value for %s", column.Name)
+}
+
+// evalConstant folds literals, optionally negated, into a Datum.
+func evalConstant(expr ast.ExprNode) (tidbtypes.Datum, bool, error) {
+	switch expr := expr.(type) {
+	case *driver.ValueExpr:
+		return expr.Datum, true, nil
+	case *ast.ParenthesesExpr:
+		return evalConstant(expr.Expr)
+	case *ast.UnaryOperationExpr:
+		d, ok, err := evalConstant(expr.V)
+		if err != nil || !ok || d.IsNull() {
+			return d, ok, err
+		}
+		switch expr.Op {
+		case opcode.Plus:
+			return d, true, nil
+		case opcode.Minus:
+			dec, err := d.ToDecimal(defaultStmtCtx())
+			if err != nil {
+				return d, false, err
+			}
+			return tidbtypes.NewDecimalDatum(tidbtypes.DecimalNeg(dec)), true, nil
+		}
+	}
+	return tidbtypes.Datum{}, false, nil
+}
+
+// goLiteral renders d, already converted to the column type, as a Go
+// expression assignable to goType. Types the generator does not know how to
+// build are left without literal.
+func goLiteral(sc *stmtctx.StatementContext, d tidbtypes.Datum, goType string) (string, error) {
+	switch goType {
+	case "string":
+		s, err := d.ToString()
+		return strconv.Quote(s), err
+	case "[]byte":
+		b, err := d.ToBytes()
+		return fmt.Sprintf("[]byte(%q)", b), err
+	case "bool":
+		v, err := d.ToBool(sc)
+		return strconv.FormatBool(v != 0), err
+	case "int8", "int16", "int32", "int64", "int":
+		v, err := d.ToInt64(sc)
+		return strconv.FormatInt(v, 10), err
+	case "uint8", "uint16", "uint32", "uint64", "uint", "dbtypes.Bit":
+		switch d.Kind() {
+		case tidbtypes.KindUint64:
+			return strconv.FormatUint(d.GetUint64(), 10), nil
+		case tidbtypes.KindMysqlBit, tidbtypes.KindBinaryLiteral:
+			v, err := d.GetBinaryLiteral().ToInt(sc)
+			return strconv.FormatUint(v, 10), err
+		}
+		v, err := d.ToInt64(sc)
+		return strconv.FormatUint(uint64(v), 10), err
+	case "float32", "float64":
+		if d.Kind() == tidbtypes.KindMysqlDecimal {
+			return d.GetMysqlDecimal().String(), nil
+		}
+		v, err := d.ToFloat64(sc)
+		return strconv.FormatFloat(v, 'g', -1, 64), err
+	case "dbtypes.Decimal", "decimal.Decimal":
+		dec, err := d.ToDecimal(sc)
+		if err != nil {
+			return "", err
+		}
+		if goType == "decimal.Decimal" {
+			// github.com/shopspring/decimal
+			return fmt.Sprintf("decimal.RequireFromString(%q)", dec.String()), nil
+		}
+		return fmt.Sprintf("dbtypes.MustParseDecimal(%q)", dec.String()), nil
+	case "time.Time":
+		if d.Kind() != tidbtypes.KindMysqlTime {
+			return "", nil
+		}
+		return timeLiteral(d.GetMysqlTime()), nil
+	case "dbtypes.Duration":
+		if d.Kind() != tidbtypes.KindMysqlDuration {
+			return "", nil
+		}
+		return fmt.Sprintf("dbtypes.MustParseDuration(%q)", d.GetMysqlDuration().String()), nil
+	case "dbtypes.MySQLTime":
+		if d.Kind() != tidbtypes.KindMysqlTime {
+			return "", nil
+		}
+		return fmt.Sprintf("dbtypes.MustParseMySQLTime(%q)", d.GetMysqlTime().String()), nil
+	}
+	switch d.Kind() {
+	case tidbtypes.KindMysqlEnum:
+		return strconv.Quote(d.GetMysqlEnum().Name), nil
+	case tidbtypes.KindMysqlSet:
+		return strconv.FormatUint(d.GetMysqlSet().Value, 10), nil
+	}
+	return "", nil
+}
+
+func timeLiteral(t tidbtypes.Time) string {
+	if t.IsZero() {
+		return "time.Time{}"
+	}
+	ct := t.CoreTime()
+	return fmt.Sprintf("time.Date(%d, time.%s, %d, %d, %d, %d, %d, time.UTC)",
+		ct.Year(), time.Month(ct.Month()), ct.Day(), ct.Hour(), ct.Minute(), ct.Second(), ct.Microsecond()*1000)
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/pingcap/parser/mysql"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/naming"
+)
+
+// DBTypesImport is the import path of the runtime package generated code uses.
+const DBTypesImport = "github.com/Sterrenhemel/ddl2struct/pkg/dbtypes"
+
+// Enum describes the Go type generated for an ENUM or SET column: a string
+// type with one constant per element for ENUM, a bitset for SET.
+type Enum struct {
+	TypeName  string
+	IsSet     bool
+	Collation string // collation elements are matched with
+	Elems     []EnumElem
+}
+
+type EnumElem struct {
+	ConstName string
+	Value     string
+	Ordinal   int // 1-based position in the declaration
+}
+
+// Values returns the elements, in declaration order.
+func (enum *Enum) Values() []string {
+	values := make([]string, 0, len(enum.Elems))
+	for _, elem := range enum.Elems {
+		values = append(values, elem.Value)
+	}
+	return values
+}
+
+// enumOf returns the Go type of an ENUM or SET column, or nil when the column
+// is of another type, EnumTypes is off or the type map or a @type annotation
+// overrides the column type. The type and its constants are declared in pkg,
+// the name space of the package.
+func (parser *DDLParser) enumOf(table *Table, column Column, pkg nameSpace) (*Enum, error) {
+	ft := column.FieldType
+	if !parser.EnumTypes || (ft.Tp != mysql.TypeEnum && ft.Tp != mysql.TypeSet) {
+		return nil, nil
+	}
+	if _, ok := parser.typeMap[typeKey(ft)]; ok {
+		return nil, nil
+	}
+	if _, ok := column.Annotations["type"]; ok {
+		return nil, nil
+	}
+	what := "column " + column.Name + " of table " + table.TableName
+	isSet := ft.Tp == mysql.TypeSet
+	// The type is declared with the identifiers the enum template derives
+	// from its name: the element list, and the Of constructor of a SET.
+	typeName, err := parser.declareGroup(pkg, table.StructName+column.FieldName, func(name string) nameSpace {
+		space := nameSpace{
+			name: "enum type of " + what,
+			naming.Namer{}.Unexported(name) + "Elems": "element list of " + what,
+		}
+		if isSet {
+			space[name+"Of"] = "constructor of " + what
+		}
+		return space
+	})
+	if err != nil {
+		return nil, err
+	}
+	enum := &Enum{
+		TypeName:  typeName,
+		IsSet:     isSet,
+		Collation: effectiveCollation(ft),
+	}
+	used := make(map[string]bool, len(ft.Elems))
+	for i, value := range ft.Elems {
+		suffix := parser.Naming.Camel(value)
+		if value == "" {
+			suffix = "Empty"
+		}
+		name := enum.TypeName + suffix
+		if suffix == "" || used[name] {
+			name = enum.TypeName + strconv.Itoa(i+1)
+		}
+		used[name] = true
+		if name, err = parser.declare(pkg, name, "element "+strconv.Quote(value)+" of "+what); err != nil {
+			return nil, err
+		}
+		enum.Elems = append(enum.Elems, EnumElem{
+			ConstName: name,
+			Value:     value,
+			Ordinal:   i + 1,
+		})
+	}
+	return enum, nil
+}
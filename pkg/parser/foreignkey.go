@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+)
+
+// ForeignKey is a FOREIGN KEY constraint of a table.
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   string // referential action, e.g. "CASCADE"; empty when not declared
+	OnUpdate   string
+}
+
+// HasColumn reports whether the column named name is one of the referencing columns.
+func (fk *ForeignKey) HasColumn(name string) bool {
+	for _, column := range fk.Columns {
+		if strings.EqualFold(column, name) {
+			return true
+		}
+	}
+	return false
+}
+
+type ForeignKeys []*ForeignKey
+
+// Find returns the position of the foreign key named name, or -1.
+func (fks ForeignKeys) Find(name string) int {
+	for i, fk := range fks {
+		if strings.EqualFold(fk.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// newForeignKey returns the foreign key of a FOREIGN KEY constraint.
+func newForeignKey(constraint *ast.Constraint) *ForeignKey {
+	fk := &ForeignKey{
+		Name:     constraint.Name,
+		RefTable: constraint.Refer.Table.Name.String(),
+	}
+	for _, key := range constraint.Keys {
+		fk.Columns = append(fk.Columns, key.Column.Name.String())
+	}
+	for _, key := range constraint.Refer.IndexPartSpecifications {
+		fk.RefColumns = append(fk.RefColumns, key.Column.Name.String())
+	}
+	if constraint.Refer.OnDelete != nil {
+		fk.OnDelete = constraint.Refer.OnDelete.ReferOpt.String()
+	}
+	if constraint.Refer.OnUpdate != nil {
+		fk.OnUpdate = constraint.Refer.OnUpdate.ReferOpt.String()
+	}
+	return fk
+}
+
+// addForeignKey registers fk on the table, naming it <table>_ibfk_<n> as
+// InnoDB does when the DDL leaves the name out. The referenced table is not
+// checked, it may come later or from another file.
+func (table *Table) addForeignKey(fk *ForeignKey) error {
+	for _, column := range fk.Columns {
+		if table.Columns.Find(column) < 0 {
+			return errors.Errorf("key column %s doesn't exist in table", column)
+		}
+	}
+	if len(fk.Columns) != len(fk.RefColumns) {
+		return errors.Errorf("incorrect foreign key definition for %s: key reference and table reference don't match", table.TableName)
+	}
+	if fk.Name == "" {
+		fk.Name = table.defaultForeignKeyName()
+	}
+	if table.ForeignKeys.Find(fk.Name) >= 0 {
+		return errors.Errorf("duplicate foreign key constraint name :%s", fk.Name)
+	}
+	table.ForeignKeys = append(table.ForeignKeys, fk)
+	return nil
+}
+
+func (table *Table) defaultForeignKeyName() string {
+	prefix := table.TableName + "_ibfk_"
+	n := 0
+	for _, fk := range table.ForeignKeys {
+		if !strings.HasPrefix(strings.ToLower(fk.Name), strings.ToLower(prefix)) {
+			continue
+		}
+		if i, err := strconv.Atoi(fk.Name[len(prefix):]); err == nil && i > n {
+			n = i
+		}
+	}
+	return fmt.Sprintf("%s%d", prefix, n+1)
+}
+
+func (table *Table) dropForeignKey(name string, ifExists bool) error {
+	pos := table.ForeignKeys.Find(name)
+	if pos < 0 {
+		if ifExists {
+			return nil
+		}
+		return errors.Errorf("can't drop foreign key :%s; check that it exists", name)
+	}
+	table.ForeignKeys = append(table.ForeignKeys[:pos], table.ForeignKeys[pos+1:]...)
+	return nil
+}
+
+// checkDropColumn fails when a foreign key of the table still needs the column.
+func (table *Table) checkDropColumn(name string) error {
+	for _, fk := range table.ForeignKeys {
+		if fk.HasColumn(name) {
+			return errors.Errorf("cannot drop column %s: needed in foreign key constraint %s", name, fk.Name)
+		}
+	}
+	return nil
+}
+
+// renameForeignKeyColumn follows a column rename in the referencing columns
+// of every foreign key of the table.
+func (table *Table) renameForeignKeyColumn(oldName, newName string) {
+	for _, fk := range table.ForeignKeys {
+		for i, column := range fk.Columns {
+			if strings.EqualFold(column, oldName) {
+				fk.Columns[i] = newName
+			}
+		}
+	}
+}
+
+// renameReferencedTable follows a table rename in the foreign keys referencing it.
+func (parser *DDLParser) renameReferencedTable(oldName, newName string) {
+	for _, tables := range parser.FileTables {
+		for _, table := range tables {
+			for _, fk := range table.ForeignKeys {
+				if fk.RefTable == oldName {
+					fk.RefTable = newName
+				}
+			}
+		}
+	}
+}
+
+// renameReferencedColumn follows a column rename in the foreign keys
+// referencing the column.
+func (parser *DDLParser) renameReferencedColumn(tableName, oldName, newName string) {
+	for _, tables := range parser.FileTables {
+		for _, table := range tables {
+			for _, fk := range table.ForeignKeys {
+				if fk.RefTable != tableName {
+					continue
+				}
+				for i, column := range fk.RefColumns {
+					if strings.EqualFold(column, oldName) {
+						fk.RefColumns[i] = newName
+					}
+				}
+			}
+		}
+	}
+}
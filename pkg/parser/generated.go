@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+
+	driver "github.com/Sterrenhemel/ddl2struct/pkg/parser_driver"
+	"github.com/Sterrenhemel/ddl2struct/pkg/types/json"
+)
+
+// JSONExtract describes a generated column that extracts a path of a JSON
+// column of the same table, as doc->'$.a' or doc->>'$.a' do. Templates use it
+// to generate an accessor evaluating the path on the JSON field.
+type JSONExtract struct {
+	Column  string // source JSON column
+	Field   string // Go field of the source column; set once types are resolved
+	Path    string // JSON path, e.g. "$.a.b"
+	Unquote bool   // the value is unquoted, as with ->> or JSON_UNQUOTE
+
+	// Set once types are resolved, when the source is a dbtypes.JSON field.
+	Accessor  string // name of the accessor method
+	Value     string // Go expression of the source dbtypes.JSON, on receiver t
+	NullCheck string // Go condition true when the source is NULL, on receiver t
+}
+
+// setGenerated records the GENERATED ALWAYS AS expression of column.
+func setGenerated(column *Column, option *ast.ColumnOption) error {
+	column.Generated = restoreNode(option.Expr)
+	column.GeneratedStored = option.Stored
+	column.JSONExtract = nil
+	extract, err := jsonExtractOf(option.Expr)
+	if err != nil {
+		return errors.Annotatef(err, "generated column %s", column.Name)
+	}
+	column.JSONExtract = extract
+	return nil
+}
+
+// jsonExtractOf returns the JSON extraction expr is made of, or nil when expr
+// is not JSON_EXTRACT(column, path), optionally within JSON_UNQUOTE.
+func jsonExtractOf(expr ast.ExprNode) (*JSONExtract, error) {
+	call, ok := expr.(*ast.FuncCallExpr)
+	if !ok {
+		return nil, nil
+	}
+	unquote := false
+	if call.FnName.L == ast.JSONUnquote && len(call.Args) == 1 {
+		unquote = true
+		if call, ok = call.Args[0].(*ast.FuncCallExpr); !ok {
+			return nil, nil
+		}
+	}
+	if call.FnName.L != ast.JSONExtract || len(call.Args) != 2 {
+		return nil, nil
+	}
+	column, ok := call.Args[0].(*ast.ColumnNameExpr)
+	if !ok {
+		return nil, nil
+	}
+	value, ok := call.Args[1].(*driver.ValueExpr)
+	if !ok {
+		return nil, nil
+	}
+	path := value.GetString()
+	if _, err := json.ParseJSONPathExpr(path); err != nil {
+		return nil, err
+	}
+	return &JSONExtract{
+		Column:  column.Name.Name.String(),
+		Path:    path,
+		Unquote: unquote,
+	}, nil
+}
+
+// resolveJSONExtract sets the accessor of a generated column extracting a
+// JSON path, when its source column exists and is a dbtypes.JSON field.
+func resolveJSONExtract(table *Table, column *Column) {
+	extract := column.JSONExtract
+	if extract == nil {
+		return
+	}
+	extract.Field, extract.Accessor, extract.Value, extract.NullCheck = "", "", "", ""
+	pos := table.Columns.Find(extract.Column)
+	if pos < 0 {
+		return
+	}
+	source := table.Columns[pos]
+	extract.Field = source.FieldName
+	if source.FieldType.Tp != mysql.TypeJSON || source.BaseType != "dbtypes.JSON" {
+		return
+	}
+	field := "t." + source.FieldName
+	switch {
+	case source.Type == source.BaseType:
+		extract.Value = field
+	case source.Type == "*"+source.BaseType:
+		extract.Value = field
+		extract.NullCheck = field + " == nil"
+	case strings.HasSuffix(source.Type, "["+source.BaseType+"]"):
+		extract.Value = field + ".V"
+		extract.NullCheck = "!" + field + ".Valid"
+	default:
+		return
+	}
+	extract.Accessor = "Extract" + column.FieldName
+}
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/parser/mysql"
+)
+
+// GormTag returns the value of the gorm struct tag of column, detailed enough
+// for AutoMigrate to rebuild the column as it was declared, comments aside.
+func (table *Table) GormTag(column Column) string {
+	return table.gormTag(column, false)
+}
+
+// gormTag returns the value of the gorm tag of column, with the comments of
+// the column and its indexes when withComment is set, as the comment option
+// of the gorm tag family asks.
+func (table *Table) gormTag(column Column, withComment bool) string {
+	settings := []string{"column:" + column.Name}
+	if sqlType := column.SQLType(); sqlType != "" {
+		if column.OnUpdate != "" {
+			sqlType += " ON UPDATE " + column.OnUpdate
+		}
+		if column.Generated != "" {
+			sqlType += " GENERATED ALWAYS AS (" + column.Generated + ")"
+			if column.GeneratedStored {
+				sqlType += " STORED"
+			} else {
+				sqlType += " VIRTUAL"
+			}
+		}
+		settings = append(settings, "type:"+sqlType)
+		if size := column.size(); size > 0 {
+			settings = append(settings, fmt.Sprintf("size:%d", size))
+		}
+		if precision, scale, ok := column.DecimalSize(); ok {
+			settings = append(settings, fmt.Sprintf("precision:%d", precision), fmt.Sprintf("scale:%d", scale))
+		}
+	}
+	if column.Serializer != "" {
+		settings = append(settings, "serializer:"+column.Serializer)
+	}
+	for _, index := range table.Indexes.OfColumn(column.Name) {
+		if index.Kind == IndexKindPrimary {
+			settings = append(settings, "primaryKey")
+			continue
+		}
+		settings = append(settings, gormIndexSetting(index, column.Name, withComment))
+	}
+	if column.AutoIncrement {
+		settings = append(settings, "autoIncrement")
+	}
+	if column.Generated != "" {
+		// MySQL computes the value, GORM must never write it.
+		settings = append(settings, "<-:false")
+	}
+	if column.NotNull {
+		settings = append(settings, "not null")
+	}
+	if column.DefaultSQL != "" && !strings.EqualFold(column.DefaultSQL, "NULL") {
+		settings = append(settings, "default:"+column.DefaultSQL)
+	}
+	if withComment && column.Comment != "" {
+		settings = append(settings, "comment:"+column.Comment)
+	}
+	for i, setting := range settings {
+		settings[i] = strings.ReplaceAll(setting, ";", `\;`)
+	}
+	return strings.Join(settings, ";")
+}
+
+// size returns the declared length of string and binary columns, 0 otherwise.
+func (column Column) size() int {
+	switch column.FieldType.Tp {
+	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString:
+		return column.FieldType.Flen
+	}
+	return 0
+}
+
+func gormIndexSetting(index *Index, columnName string, withComment bool) string {
+	var setting string
+	switch index.Kind {
+	case IndexKindUnique:
+		setting = "uniqueIndex:" + index.Name
+	case IndexKindFullText:
+		setting = "index:" + index.Name + ",class:FULLTEXT"
+	case IndexKindSpatial:
+		setting = "index:" + index.Name + ",class:SPATIAL"
+	default:
+		setting = "index:" + index.Name
+	}
+	for i, column := range index.Columns {
+		if !strings.EqualFold(column.Name, columnName) {
+			continue
+		}
+		if len(index.Columns) > 1 {
+			setting += fmt.Sprintf(",priority:%d", i+1)
+		}
+		if column.Length > 0 {
+			setting += fmt.Sprintf(",length:%d", column.Length)
+		}
+		break
+	}
+	if withComment && index.Comment != "" {
+		// GORM splits index settings at commas and has no escape for them.
+		setting += ",comment:" + strings.ReplaceAll(index.Comment, ",", " ")
+	}
+	return setting
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"go/parser"
+	"reflect"
+	"testing"
+)
+
+const gormCommentTable = "CREATE TABLE t (id int PRIMARY KEY, name varchar(32) COMMENT 'the `name`, \"quoted\"; ok', KEY idx_name (name) COMMENT 'by `name`, then id');\n"
+
+func TestGormTag(t *testing.T) {
+	for _, test := range []struct {
+		tags string
+		gorm string
+	}{
+		{"gorm", "column:name;type:varchar(32);size:32;index:idx_name"},
+		{"gorm:comment", "column:name;type:varchar(32);size:32;index:idx_name,comment:by `name`  then id;comment:the `name`, \"quoted\"\\; ok"},
+	} {
+		p := New("test.sql", "", "test")
+		if err := p.SetTags(test.tags); err != nil {
+			t.Fatal(err)
+		}
+		table := tableOf(t, parseSQL(t, p, gormCommentTable), "t")
+		tag := table.StructTag(*columnOf(t, table, "name"))
+		// The tag must be valid in a generated field.
+		if _, err := parser.ParseExpr("struct{ Name string `" + tag + "` }"); err != nil {
+			t.Errorf("%s: tag %s: %v", test.tags, tag, err)
+		}
+		if got := reflect.StructTag(tag).Get("gorm"); got != test.gorm {
+			t.Errorf("%s: got gorm tag %q, want %q", test.tags, got, test.gorm)
+		}
+	}
+}
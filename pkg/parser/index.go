@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+)
+
+// primaryKeyName is the name MySQL always gives to the primary key.
+const primaryKeyName = "PRIMARY"
+
+func constraintIndexKind(tp ast.ConstraintType) (IndexKind, bool) {
+	switch tp {
+	case ast.ConstraintPrimaryKey:
+		return IndexKindPrimary, true
+	case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		return IndexKindUnique, true
+	case ast.ConstraintKey, ast.ConstraintIndex:
+		return IndexKindPlain, true
+	case ast.ConstraintFulltext:
+		return IndexKindFullText, true
+	}
+	return "", false
+}
+
+func indexKeyKind(tp ast.IndexKeyType) IndexKind {
+	switch tp {
+	case ast.IndexKeyTypeUnique:
+		return IndexKindUnique
+	case ast.IndexKeyTypeSpatial:
+		return IndexKindSpatial
+	case ast.IndexKeyTypeFullText:
+		return IndexKindFullText
+	}
+	return IndexKindPlain
+}
+
+func newIndex(kind IndexKind, name string, keys []*ast.IndexPartSpecification, option *ast.IndexOption) *Index {
+	index := &Index{
+		Name: name,
+		Kind: kind,
+	}
+	for _, key := range keys {
+		if key.Expr != nil {
+			index.Columns = append(index.Columns, IndexColumn{Expr: restoreNode(key.Expr)})
+			continue
+		}
+		column := IndexColumn{Name: key.Column.Name.String()}
+		if key.Length > 0 {
+			column.Length = key.Length
+		}
+		index.Columns = append(index.Columns, column)
+	}
+	if option != nil {
+		index.Invisible = option.Visibility == ast.IndexVisibilityInvisible
+		index.Comment = option.Comment
+	}
+	return index
+}
+
+// addConstraint adds the index, foreign key or check described by a table
+// constraint.
+func (parser *DDLParser) addConstraint(table *Table, constraint *ast.Constraint) error {
+	switch constraint.Tp {
+	case ast.ConstraintForeignKey:
+		return table.addForeignKey(newForeignKey(constraint))
+	case ast.ConstraintCheck:
+		return table.addCheck(newCheck(constraint.Name, constraint.Expr, constraint.Enforced))
+	}
+	kind, ok := constraintIndexKind(constraint.Tp)
+	if !ok {
+		return nil
+	}
+	return table.addIndex(newIndex(kind, constraint.Name, constraint.Keys, constraint.Option), constraint.IfNotExists)
+}
+
+// addColumnIndexes adds the indexes declared inline by PRIMARY KEY and UNIQUE
+// column options.
+func (parser *DDLParser) addColumnIndexes(table *Table, col *ast.ColumnDef) error {
+	keys := []*ast.IndexPartSpecification{{Column: col.Name}}
+	for _, option := range col.Options {
+		var err error
+		switch option.Tp {
+		case ast.ColumnOptionPrimaryKey:
+			err = table.addIndex(newIndex(IndexKindPrimary, "", keys, nil), false)
+		case ast.ColumnOptionUniqKey:
+			err = table.addIndex(newIndex(IndexKindUnique, "", keys, nil), false)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addIndex registers index on the table, naming it the way MySQL does when the
+// DDL leaves the name out.
+func (table *Table) addIndex(index *Index, ifNotExists bool) error {
+	for _, column := range index.Columns {
+		if column.Expr == "" && table.Columns.Find(column.Name) < 0 {
+			return errors.Errorf("key column %s doesn't exist in table", column.Name)
+		}
+	}
+	if index.Kind == IndexKindPrimary {
+		if table.Indexes.Primary() != nil {
+			return errors.New("multiple primary key defined")
+		}
+		index.Name = primaryKeyName
+	} else if index.Name == "" {
+		index.Name = table.defaultIndexName(index)
+	}
+	if table.Indexes.Find(index.Name) >= 0 {
+		if ifNotExists {
+			return nil
+		}
+		return errors.Errorf("duplicate key name :%s", index.Name)
+	}
+	table.Indexes = append(table.Indexes, index)
+	return nil
+}
+
+func (table *Table) defaultIndexName(index *Index) string {
+	base := "expression_index"
+	if len(index.Columns) > 0 && index.Columns[0].Name != "" {
+		base = index.Columns[0].Name
+	}
+	name := base
+	for i := 2; table.Indexes.Find(name) >= 0 || strings.EqualFold(name, primaryKeyName); i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	return name
+}
+
+func (table *Table) dropIndex(name string, ifExists bool) error {
+	pos := table.Indexes.Find(name)
+	if pos < 0 {
+		if ifExists {
+			return nil
+		}
+		return errors.Errorf("can't drop index :%s; check that it exists", name)
+	}
+	table.Indexes = append(table.Indexes[:pos], table.Indexes[pos+1:]...)
+	return nil
+}
+
+// dropIndexColumn removes a dropped column from the key parts of every index,
+// dropping indexes left without key parts, as MySQL does.
+func (table *Table) dropIndexColumn(name string) {
+	indexes := table.Indexes[:0]
+	for _, index := range table.Indexes {
+		columns := index.Columns[:0]
+		for _, column := range index.Columns {
+			if !strings.EqualFold(column.Name, name) {
+				columns = append(columns, column)
+			}
+		}
+		index.Columns = columns
+		if len(index.Columns) > 0 {
+			indexes = append(indexes, index)
+		}
+	}
+	table.Indexes = indexes
+}
+
+// renameIndexColumn follows a column rename in the key parts of every index.
+func (table *Table) renameIndexColumn(oldName, newName string) {
+	for _, index := range table.Indexes {
+		for i, column := range index.Columns {
+			if strings.EqualFold(column.Name, oldName) {
+				index.Columns[i].Name = newName
+			}
+		}
+	}
+}
+
+func (parser *DDLParser) parseCreateIndexStmt(stmt *ast.CreateIndexStmt) error {
+	tableName := stmt.Table.Name.String()
+	_, table := parser.findTable(tableName)
+	if table == nil {
+		return errors.Errorf("create index on unknown table :%s", tableName)
+	}
+	parser.touchTable(table)
+	index := newIndex(indexKeyKind(stmt.KeyType), stmt.IndexName, stmt.IndexPartSpecifications, stmt.IndexOption)
+	return errors.Annotatef(table.addIndex(index, stmt.IfNotExists), "create index on %s", tableName)
+}
+
+func (parser *DDLParser) parseDropIndexStmt(stmt *ast.DropIndexStmt) error {
+	tableName := stmt.Table.Name.String()
+	_, table := parser.findTable(tableName)
+	if table == nil {
+		return errors.Errorf("drop index on unknown table :%s", tableName)
+	}
+	parser.touchTable(table)
+	return errors.Annotatef(table.dropIndex(stmt.IndexName, stmt.IfExists), "drop index on %s", tableName)
+}
@@ -0,0 +1,156 @@
+package parser
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/naming"
+)
+
+// CollisionStrategy tells what to do when two declarations get the same Go
+// name, e.g. the columns user_id and userId.
+type CollisionStrategy string
+
+const (
+	// CollisionError fails the generation.
+	CollisionError CollisionStrategy = "error"
+	// CollisionSuffix numbers the names declared last, in table and column
+	// order: UserId, UserId2.
+	CollisionSuffix CollisionStrategy = "suffix"
+)
+
+// ParseCollisionStrategy validates a strategy name given on the command line.
+func ParseCollisionStrategy(name string) (CollisionStrategy, error) {
+	switch strategy := CollisionStrategy(name); strategy {
+	case "":
+		return CollisionError, nil
+	case CollisionError, CollisionSuffix:
+		return strategy, nil
+	}
+	return "", errors.Errorf("unknown collision strategy :%s", name)
+}
+
+// nameSpace holds the names declared in a package or a struct, with what
+// declares them.
+type nameSpace map[string]string
+
+// declare declares name for what in space, and returns the name it gets.
+func (parser *DDLParser) declare(space nameSpace, name, what string) (string, error) {
+	return parser.declareGroup(space, name, func(name string) nameSpace {
+		return nameSpace{name: what}
+	})
+}
+
+// declareGroup declares the names group returns for name, such as a type and
+// the functions generated with it, and returns the name they get. Names of a
+// group are numbered together, so that they keep matching.
+func (parser *DDLParser) declareGroup(space nameSpace, name string, group func(name string) nameSpace) (string, error) {
+	candidate := name
+	for n := 2; ; n++ {
+		decls := group(candidate)
+		names := make([]string, 0, len(decls))
+		for declared := range decls {
+			names = append(names, declared)
+		}
+		sort.Strings(names)
+		free := true
+		for _, declared := range names {
+			other, ok := space[declared]
+			if !ok {
+				continue
+			}
+			if parser.Collisions != CollisionSuffix {
+				return "", errors.Errorf("%s and %s are both named %s, rename one in the config or set collisions to suffix", other, decls[declared], declared)
+			}
+			free = false
+		}
+		if free {
+			for declared, what := range decls {
+				space[declared] = what
+			}
+			return candidate, nil
+		}
+		candidate = name + strconv.Itoa(n)
+	}
+}
+
+// Renaming tells how the struct of a table was named, for the naming report.
+type Renaming struct {
+	TableName  string
+	StructName string
+	Rule       string // the config setting or rule giving StructName
+}
+
+// structName returns the struct name of the table named tableName, as named
+// by the config, by the first table name rule matching it or by camel casing,
+// and a description of how it was derived.
+func (parser *DDLParser) structName(tableName string) (string, string) {
+	if name := parser.config.Table(tableName).Name; name != "" {
+		return name, "tables." + tableName + ".name"
+	}
+	rule, i := parser.config.TableNameRule(tableName)
+	if rule == nil {
+		return parser.Naming.Exported(tableName), "camel case"
+	}
+	var steps []string
+	if rule.Match != "" {
+		steps = append(steps, "match "+strconv.Quote(rule.Match))
+	}
+	base := tableName
+	if rule.Strip != "" {
+		base = rule.Stripped(base)
+		steps = append(steps, "strip "+strconv.Quote(rule.Strip))
+	}
+	if rule.Singular {
+		base = Singularize(base)
+		steps = append(steps, "singular")
+	}
+	if rule.Prefix != "" {
+		steps = append(steps, "prefix "+rule.Prefix)
+	}
+	if rule.Suffix != "" {
+		steps = append(steps, "suffix "+rule.Suffix)
+	}
+	name := naming.Export(rule.Prefix + parser.Naming.Camel(base) + rule.Suffix)
+	return name, "table_names." + strconv.Itoa(i) + ": " + strings.Join(steps, ", ")
+}
+
+// structSpace returns the name space of the struct of table, holding the
+// methods generated for it: TableName, and Validate when it may be.
+func (parser *DDLParser) structSpace(table *Table) nameSpace {
+	space := nameSpace{"TableName": "method TableName of table " + table.TableName}
+	if parser.Validate || len(table.Checks) > 0 {
+		space["Validate"] = "method Validate of table " + table.TableName
+	}
+	return space
+}
+
+// outputTable is a table with the output file it is generated in.
+type outputTable struct {
+	*Table
+	fileName string
+}
+
+// outputTables returns the tables of the session ordered by name, so that
+// collisions are resolved the same way on every run.
+func (parser *DDLParser) outputTables() []outputTable {
+	var tables []outputTable
+	for fileName, fileTables := range parser.FileTables {
+		for _, table := range fileTables {
+			tables = append(tables, outputTable{Table: table, fileName: fileName})
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].TableName < tables[j].TableName
+	})
+	return tables
+}
+
+// packageOf returns the package an output file belongs to: its directory.
+func packageOf(fileName string) string {
+	return path.Dir(fileName)
+}
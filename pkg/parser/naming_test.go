@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratedNameCollisions(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		sql          string
+		constructors bool
+		collisions   CollisionStrategy
+		err          string            // empty when the generation succeeds
+		structs      map[string]string // struct names by table
+		enums        map[string]string // enum type names by column of table t
+	}{
+		{
+			name:    "no constructors",
+			sql:     "CREATE TABLE users (id int); CREATE TABLE new_users (id int);",
+			structs: map[string]string{"users": "Users", "new_users": "NewUsers"},
+		},
+		{
+			name:         "constructor error",
+			sql:          "CREATE TABLE users (id int); CREATE TABLE new_users (id int);",
+			constructors: true,
+			err:          "table new_users and constructor of table users are both named NewUsers",
+		},
+		{
+			name:         "constructor suffix",
+			sql:          "CREATE TABLE users (id int); CREATE TABLE new_users (id int);",
+			constructors: true,
+			collisions:   CollisionSuffix,
+			structs:      map[string]string{"users": "Users2", "new_users": "NewUsers"},
+		},
+		{
+			name: "set constructor error",
+			sql:  "CREATE TABLE t (flags set('of','x'));",
+			err:  `constructor of column flags of table t and element "of" of column flags of table t are both named TFlagsOf`,
+		},
+		{
+			name:       "set constructor suffix",
+			sql:        "CREATE TABLE t (flags set('of','x'));",
+			collisions: CollisionSuffix,
+			enums:      map[string]string{"flags": "TFlags"},
+		},
+		{
+			name: "enum type and struct",
+			sql:  "CREATE TABLE t (status enum('a')); CREATE TABLE t_status (id int);",
+			err:  "table t_status and enum type of column status of table t are both named TStatus",
+		},
+	} {
+		parser := New("test.sql", "", "test")
+		parser.EnumTypes = true
+		parser.Constructors = test.constructors
+		if test.collisions != "" {
+			parser.Collisions = test.collisions
+		}
+		err := parser.ParseInputs(Input{File: "test.sql", SQL: test.sql})
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: got %v, want %s", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		for tableName, structName := range test.structs {
+			if got := tableOf(t, parser, tableName).StructName; got != structName {
+				t.Errorf("%s: table %s: got %s, want %s", test.name, tableName, got, structName)
+			}
+		}
+		for columnName, typeName := range test.enums {
+			enum := columnOf(t, tableOf(t, parser, "t"), columnName).Enum
+			if enum == nil || enum.TypeName != typeName {
+				t.Errorf("%s: column %s: got %+v, want type %s", test.name, columnName, enum, typeName)
+				continue
+			}
+			for _, elem := range enum.Elems {
+				if elem.ConstName == typeName+"Of" {
+					t.Errorf("%s: element %s is named as the constructor %sOf", test.name, elem.Value, typeName)
+				}
+			}
+		}
+	}
+}
+
+func TestTagNameCollisions(t *testing.T) {
+	for _, test := range []struct {
+		tags string
+		sql  string
+		err  string // empty when the tags differ
+	}{
+		{"json", "CREATE TABLE t (user_id int, userId int)", `column user_id of table t and column userId of table t are both tagged json:"user_id"`},
+		{"json:camel", "CREATE TABLE t (user_id int, userId int)", `both tagged json:"userId"`},
+		{"json:column", "CREATE TABLE t (user_id int, userId int)", ""},
+		{"yaml,db", "CREATE TABLE t (user_id int, userId int)", `both tagged yaml:"user_id"`},
+		{"mapstructure:camel", "CREATE TABLE t (user_id int, userId int)", `both tagged mapstructure:"userId"`},
+		{"xorm:snake", "CREATE TABLE t (user_id int, userId int)", `both tagged xorm:"user_id"`},
+		{"gorm", "CREATE TABLE t (user_id int, userId int)", ""},
+		{"json", "CREATE TABLE t (user_id int, userId int COMMENT '@json=uid')", ""},
+		{"json", "CREATE TABLE t (user_id int, userId int COMMENT '@json=-')", ""},
+		{"json", "CREATE TABLE t (a int, b int COMMENT '@json=a,omitempty')", `column a of table t and column b of table t are both tagged json:"a"`},
+		{"validate", "CREATE TABLE t (user_id int NOT NULL, userId int NOT NULL)", ""},
+	} {
+		parser := New("test.sql", "", "test")
+		parser.Collisions = CollisionSuffix
+		if err := parser.SetTags(test.tags); err != nil {
+			t.Fatalf("%s: %v", test.tags, err)
+		}
+		err := parser.ParseInputs(Input{File: "test.sql", SQL: test.sql})
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%s %s: %v", test.tags, test.sql, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s %s: got %v, want %s", test.tags, test.sql, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// NullableStrategy decides how columns that accept NULL are represented.
+type NullableStrategy string
+
+const (
+	// NullableNone keeps plain value types, NULL scans as the zero value.
+	NullableNone NullableStrategy = "none"
+	// NullablePointer uses a pointer to the value type, e.g. *int64.
+	NullablePointer NullableStrategy = "pointer"
+	// NullableSQL uses the database/sql Null types, e.g. sql.NullInt64, or
+	// the Null type of the package of the value type, e.g. dbtypes.NullDecimal.
+	// It falls back to pointers for types that have no Null type.
+	NullableSQL NullableStrategy = "sql"
+	// NullableGeneric uses a generic Null[T] type, sql.Null[T] by default,
+	// which the generated code needs Go 1.22 for.
+	NullableGeneric NullableStrategy = "generic"
+)
+
+// DefaultGenericNullType is the generic type used by NullableGeneric when no
+// other type is configured.
+const DefaultGenericNullType = "database/sql.Null"
+
+var sqlNullTypes = map[string]string{
+	"string":    "sql.NullString",
+	"int64":     "sql.NullInt64",
+	"int32":     "sql.NullInt32",
+	"int16":     "sql.NullInt16",
+	"uint8":     "sql.NullByte",
+	"float64":   "sql.NullFloat64",
+	"bool":      "sql.NullBool",
+	"time.Time": "sql.NullTime",
+
+	"dbtypes.Decimal":   "dbtypes.NullDecimal",
+	"dbtypes.MySQLTime": "dbtypes.NullMySQLTime",
+	"dbtypes.Duration":  "dbtypes.NullDuration",
+	"dbtypes.Bit":       "dbtypes.NullBit",
+	"decimal.Decimal":   "decimal.NullDecimal", // github.com/shopspring/decimal
+}
+
+// genericNullValue returns the Go expression of a valid value of nullType, an
+// instance of a generic null type, holding value of type baseType.
+type genericNullValue func(nullType, baseType, value string) string
+
+// genericNullValues are the generic null types whose valid values constructors
+// know how to build, by import/path.Name. Defaults of columns of other
+// generic types are left out of constructors.
+var genericNullValues = map[string]genericNullValue{
+	DefaultGenericNullType: func(nullType, baseType, value string) string {
+		// sql.Null[int64]{V: v, Valid: true}
+		return nullType + "{V: " + value + ", Valid: true}"
+	},
+	"github.com/samber/mo.Option": func(nullType, baseType, value string) string {
+		// mo.Some[int64](v)
+		return nullType[:strings.Index(nullType, ".")] + ".Some[" + baseType + "](" + value + ")"
+	},
+}
+
+// ParseNullableStrategy validates a strategy name given on the command line.
+func ParseNullableStrategy(name string) (NullableStrategy, error) {
+	switch strategy := NullableStrategy(name); strategy {
+	case "":
+		return NullableNone, nil
+	case NullableNone, NullablePointer, NullableSQL, NullableGeneric:
+		return strategy, nil
+	}
+	return "", errors.Errorf("unknown nullable strategy :%s", name)
+}
+
+// SetGenericNullType sets the generic type used by NullableGeneric, given as
+// "import/path.Name".
+func (parser *DDLParser) SetGenericNullType(typ string) error {
+	if !strings.Contains(typ, ".") {
+		return errors.Errorf("generic null type must look like import/path.Name :%s", typ)
+	}
+	qualified, err := parser.qualifyType(typ)
+	if err != nil {
+		return err
+	}
+	parser.genericNullType = qualified
+	parser.genericNullValue = genericNullValues[typ]
+	return nil
+}
+
+// nullableType returns the Go type of a column that accepts NULL, under
+// strategy.
+func (parser *DDLParser) nullableType(strategy NullableStrategy, baseType string) string {
+	if strings.HasPrefix(baseType, "[]") || strings.HasPrefix(baseType, "*") {
+		// nil already stands for NULL.
+		return baseType
+	}
+	switch strategy {
+	case NullablePointer:
+		return "*" + baseType
+	case NullableSQL:
+		if nullType, ok := sqlNullTypes[baseType]; ok {
+			return nullType
+		}
+		return "*" + baseType
+	case NullableGeneric:
+		return parser.genericNullType + "[" + baseType + "]"
+	}
+	return baseType
+}
@@ -0,0 +1,467 @@
+package parser_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/config"
+	"github.com/Sterrenhemel/ddl2struct/pkg/naming"
+	"github.com/Sterrenhemel/ddl2struct/pkg/parser"
+	"github.com/Sterrenhemel/ddl2struct/pkg/tpl"
+)
+
+// generate parses sql as the single input of a parser set up by setup, when
+// not nil, and returns the Go code the built-in template renders for it, the
+// output files in name order. Runs of blanks are collapsed into one space,
+// so that expected lines do not depend on the alignment of gofmt.
+func generate(t *testing.T, sql string, setup func(*parser.DDLParser)) string {
+	t.Helper()
+	ddlParser := parser.New("test.sql", "", "test")
+	if setup != nil {
+		setup(ddlParser)
+	}
+	if err := ddlParser.ParseInputs(parser.Input{File: "test.sql", SQL: sql}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	fileNames := make([]string, 0, len(ddlParser.FileTables))
+	for fileName := range ddlParser.FileTables {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	var code []string
+	for _, fileName := range fileNames {
+		data := tpl.NewTemplateVar(tpl.ModeFile, ddlParser.FileTables[fileName])
+		data.InputFiles = ddlParser.FileInputs(fileName)
+		data.InputFile = data.InputFiles[0]
+		data.PackageName = ddlParser.FilePackage(fileName)
+		data.Imports = ddlParser.FileImports[fileName]
+		data.WithConstructor = ddlParser.Constructors
+		data.WithTag = len(ddlParser.Tags) > 0
+		source, err := tpl.Builtin().Execute(fileName, data)
+		if err != nil {
+			t.Fatalf("render %s: %v", fileName, err)
+		}
+		code = append(code, string(source))
+	}
+	return strings.Join(strings.Fields(strings.Join(code, "\n")), " ")
+}
+
+// outputTest is a case of the generated code of sql: it holds every line of
+// want and none of those of absent, both compared with blanks collapsed.
+type outputTest struct {
+	name   string
+	sql    string
+	setup  func(*parser.DDLParser)
+	want   []string
+	absent []string
+}
+
+func runOutputTests(t *testing.T, tests []outputTest) {
+	t.Helper()
+	for _, test := range tests {
+		code := generate(t, test.sql, test.setup)
+		for _, line := range test.want {
+			if !strings.Contains(code, strings.Join(strings.Fields(line), " ")) {
+				t.Errorf("%s: no %s in\n%s", test.name, line, code)
+			}
+		}
+		for _, line := range test.absent {
+			if strings.Contains(code, strings.Join(strings.Fields(line), " ")) {
+				t.Errorf("%s: unexpected %s in\n%s", test.name, line, code)
+			}
+		}
+	}
+}
+
+func TestTableStatementOutput(t *testing.T) {
+	runOutputTests(t, []outputTest{
+		{
+			name:   "drop and recreate",
+			sql:    "CREATE TABLE a (id int); DROP TABLE a; CREATE TABLE a (name varchar(8));",
+			want:   []string{"type A struct{ Name string `json:\"name\""},
+			absent: []string{"Id int32"},
+		},
+		{
+			name: "drop if exists first",
+			sql:  "DROP TABLE IF EXISTS a; CREATE TABLE a (id int);",
+			want: []string{"type A struct{ Id int32"},
+		},
+		{
+			name:   "drop one of two",
+			sql:    "CREATE TABLE a (id int); CREATE TABLE b (id int); DROP TABLE a;",
+			want:   []string{"type B struct"},
+			absent: []string{"type A struct"},
+		},
+		{
+			name:   "rename table",
+			sql:    "CREATE TABLE a (id int); RENAME TABLE a TO b;",
+			want:   []string{"type B struct{ Id int32", `func (B) TableName() string { return "b" }`},
+			absent: []string{"type A struct"},
+		},
+		{
+			name:   "alter table rename",
+			sql:    "CREATE TABLE a (id int); ALTER TABLE a RENAME TO c;",
+			want:   []string{"type C struct", `return "c"`},
+			absent: []string{"type A struct"},
+		},
+		{
+			name: "truncate",
+			sql:  "CREATE TABLE a (id int); TRUNCATE TABLE a;",
+			want: []string{"type A struct{ Id int32"},
+		},
+	})
+}
+
+func TestIndexOutput(t *testing.T) {
+	runOutputTests(t, []outputTest{
+		{
+			name: "constraints",
+			sql: `CREATE TABLE t (
+				id bigint, email varchar(128), name varchar(64), bio text, org int,
+				PRIMARY KEY pk (id),
+				UNIQUE KEY uk_email (email),
+				KEY idx_org_name (org, name(10)),
+				FULLTEXT KEY ft_bio (bio)
+			)`,
+			want: []string{
+				`gorm:"column:id;type:bigint(20);primaryKey;not null"`,
+				`gorm:"column:email;type:varchar(128);size:128;uniqueIndex:uk_email"`,
+				`gorm:"column:name;type:varchar(64);size:64;index:idx_org_name,priority:2,length:10"`,
+				`gorm:"column:bio;type:text;index:ft_bio,class:FULLTEXT"`,
+				`gorm:"column:org;type:int(11);index:idx_org_name,priority:1"`,
+			},
+		},
+		{
+			name: "column options",
+			sql:  "CREATE TABLE t (id int PRIMARY KEY, code char(4) UNIQUE)",
+			want: []string{
+				`gorm:"column:id;type:int(11);primaryKey;not null"`,
+				`gorm:"column:code;type:char(4);size:4;uniqueIndex:code"`,
+			},
+		},
+		{
+			name: "create index",
+			sql:  "CREATE TABLE t (id int, name varchar(8)); CREATE INDEX idx_name ON t (name); CREATE UNIQUE INDEX uk_id ON t (id);",
+			want: []string{
+				`gorm:"column:id;type:int(11);uniqueIndex:uk_id"`,
+				`gorm:"column:name;type:varchar(8);size:8;index:idx_name"`,
+			},
+		},
+		{
+			name:   "drop index",
+			sql:    "CREATE TABLE t (id int, name varchar(8), KEY idx_name (name)); DROP INDEX idx_name ON t;",
+			want:   []string{`gorm:"column:name;type:varchar(8);size:8"`},
+			absent: []string{"idx_name"},
+		},
+	})
+}
+
+func TestNullableOutput(t *testing.T) {
+	const sql = "CREATE TABLE t (id bigint PRIMARY KEY, n bigint NULL, m bigint NOT NULL, d decimal(5,2), b blob)"
+	nullable := func(strategy parser.NullableStrategy, nullType string) func(*parser.DDLParser) {
+		return func(ddlParser *parser.DDLParser) {
+			ddlParser.Nullable = strategy
+			if nullType != "" {
+				if err := ddlParser.SetGenericNullType(nullType); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+	runOutputTests(t, []outputTest{
+		{
+			name:   "none",
+			sql:    sql,
+			want:   []string{"Id int64", "N int64", "M int64", "D dbtypes.Decimal", "B []byte"},
+			absent: []string{`"database/sql"`},
+		},
+		{
+			name:   "pointer",
+			sql:    sql,
+			setup:  nullable(parser.NullablePointer, ""),
+			want:   []string{"Id int64", "N *int64", "M int64", "D *dbtypes.Decimal", "B []byte"},
+			absent: []string{`"database/sql"`},
+		},
+		{
+			name:  "sql",
+			sql:   sql,
+			setup: nullable(parser.NullableSQL, ""),
+			want: []string{
+				`"database/sql"`, `"github.com/Sterrenhemel/ddl2struct/pkg/dbtypes"`,
+				"Id int64", "N sql.NullInt64", "M int64", "D dbtypes.NullDecimal", "B []byte",
+			},
+		},
+		{
+			name:  "generic",
+			sql:   sql,
+			setup: nullable(parser.NullableGeneric, ""),
+			want:  []string{`"database/sql"`, "Id int64", "N sql.Null[int64]", "M int64", "D sql.Null[dbtypes.Decimal]", "B []byte"},
+		},
+		{
+			name:   "generic of another package",
+			sql:    sql,
+			setup:  nullable(parser.NullableGeneric, "github.com/samber/mo.Option"),
+			want:   []string{`"github.com/samber/mo"`, "N mo.Option[int64]", "M int64"},
+			absent: []string{`"database/sql"`},
+		},
+	})
+}
+
+func TestBinaryTypeOutput(t *testing.T) {
+	runOutputTests(t, []outputTest{
+		{
+			name: "binary types",
+			sql: `CREATE TABLE t (
+				bn binary(16), vb varbinary(255), tb tinyblob, bl blob, mb mediumblob, lb longblob,
+				cs varchar(8) CHARACTER SET binary
+			)`,
+			want: []string{"Bn []byte", "Vb []byte", "Tb []byte", "Bl []byte", "Mb []byte", "Lb []byte", "Cs []byte"},
+		},
+		{
+			name:   "text with binary collation",
+			sql:    "CREATE TABLE t (vc varchar(8) COLLATE utf8mb4_bin, c char(4) BINARY, tx text COLLATE utf8mb4_bin)",
+			want:   []string{"Vc string", "C string", "Tx string"},
+			absent: []string{"[]byte"},
+		},
+		{
+			name: "bit",
+			sql:  "CREATE TABLE t (flag bit(1), mask bit(8))",
+			want: []string{"Flag dbtypes.Bit", "Mask dbtypes.Bit"},
+		},
+	})
+}
+
+func TestDefaultOutput(t *testing.T) {
+	constructors := func(ddlParser *parser.DDLParser) { ddlParser.Constructors = true }
+	runOutputTests(t, []outputTest{
+		{
+			name: "literals",
+			sql: `CREATE TABLE t (
+				id int AUTO_INCREMENT PRIMARY KEY,
+				n int NOT NULL DEFAULT -5,
+				u int unsigned NOT NULL DEFAULT '7',
+				f double NOT NULL DEFAULT 1.5e3,
+				d decimal(5,2) NOT NULL DEFAULT 3.1,
+				b bit(4) NOT NULL DEFAULT b'101',
+				h varbinary(4) NOT NULL DEFAULT x'4142',
+				s varchar(16) NOT NULL DEFAULT 'it''s "q"\n',
+				e enum('a','b') NOT NULL DEFAULT 'b',
+				ts timestamp(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+				dt datetime NOT NULL DEFAULT '2021-03-04 05:06:07',
+				tm time NOT NULL DEFAULT '12:34:56',
+				none int
+			)`,
+			setup: constructors,
+			want: []string{
+				"func NewT() T { return T{",
+				"N: -5,",
+				"U: 7,",
+				"F: 1500,",
+				`D: dbtypes.MustParseDecimal("3.10"),`,
+				"B: 5,",
+				`H: []byte("AB"),`,
+				`S: "it's \"q\"\n",`,
+				`E: "b",`,
+				"Ts: time.Now().Truncate(time.Millisecond),",
+				"Dt: time.Date(2021, time.March, 4, 5, 6, 7, 0, time.UTC),",
+				`Tm: dbtypes.MustParseDuration("12:34:56"),`,
+			},
+			absent: []string{"Id:", "None:"},
+		},
+		{
+			name: "gorm tag",
+			sql:  "CREATE TABLE t (n int NOT NULL DEFAULT -5, s varchar(8) DEFAULT 'x')",
+			want: []string{`gorm:"column:n;type:int(11);not null;default:-5"`, `gorm:"column:s;type:varchar(8);size:8;default:'x'"`},
+		},
+		{
+			name:   "no constructor",
+			sql:    "CREATE TABLE t (n int NOT NULL DEFAULT -5)",
+			absent: []string{"func NewT"},
+		},
+	})
+}
+
+func TestGeneratedColumnOutput(t *testing.T) {
+	runOutputTests(t, []outputTest{
+		{
+			name: "read-only columns",
+			sql:  "CREATE TABLE t (id int, v int AS (id * 2), s int GENERATED ALWAYS AS (id + 1) STORED)",
+			want: []string{
+				`gorm:"column:v;type:int(11) GENERATED ALWAYS AS (id*2) VIRTUAL;<-:false"`,
+				`gorm:"column:s;type:int(11) GENERATED ALWAYS AS (id+1) STORED;<-:false"`,
+			},
+			absent: []string{"func (t T) Extract"},
+		},
+		{
+			name: "JSON accessors",
+			sql: `CREATE TABLE t (
+				doc json,
+				name varchar(64) AS (doc->>'$.name'),
+				age int AS (json_extract(doc, '$.age')) STORED,
+				tag varchar(8) AS (json_unquote(doc->'$.tags[0]'))
+			)`,
+			want: []string{
+				`func (t T) ExtractName() (string, bool, error) { return t.Doc.GetUnquoted("$.name") }`,
+				`func (t T) ExtractAge() (dbtypes.JSON, bool, error) { return t.Doc.Get("$.age") }`,
+				`func (t T) ExtractTag() (string, bool, error) { return t.Doc.GetUnquoted("$.tags[0]") }`,
+			},
+		},
+		{
+			name:   "not a JSON column",
+			sql:    "CREATE TABLE t (doc text, name varchar(64) AS (doc->>'$.name'))",
+			absent: []string{"func (t T) ExtractName"},
+		},
+	})
+}
+
+// withConfig returns a setup applying the YAML config content.
+func withConfig(t *testing.T, content string) func(*parser.DDLParser) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "ddl2struct.yaml")
+	if err := ioutil.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func(ddlParser *parser.DDLParser) {
+		if err := ddlParser.SetConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestConfigOutput(t *testing.T) {
+	setup := withConfig(t, `
+tables:
+  users:
+    name: Account
+    nullable: pointer
+    columns:
+      id:
+        name: AccountID
+        type: github.com/google/uuid.UUID
+  logs:
+    with_tags: false
+columns:
+  "*.email":
+    tags:
+      validate: email
+`)
+	runOutputTests(t, []outputTest{
+		{
+			name:  "table and column settings",
+			sql:   "CREATE TABLE users (id binary(16) PRIMARY KEY, email varchar(64), age int); CREATE TABLE logs (msg text, email varchar(64));",
+			setup: setup,
+			want: []string{
+				`"github.com/google/uuid"`,
+				"type Account struct{",
+				"AccountID uuid.UUID `json:\"id\"",
+				"Email *string `json:\"email\" gorm:\"column:email;type:varchar(64);size:64\" validate:\"email\"`",
+				"Age *int32",
+				`func (Account) TableName() string { return "users" }`,
+				"type Logs struct{ Msg string Email string `validate:\"email\"` }",
+			},
+		},
+	})
+}
+
+func TestNamingOutput(t *testing.T) {
+	runOutputTests(t, []outputTest{
+		{
+			name: "plain camel case",
+			sql:  "CREATE TABLE user_profile (user_id int, avatar_url varchar(255), `type` int, `1st_place` int)",
+			want: []string{
+				"type UserProfile struct{",
+				"UserId int32 `json:\"user_id\"",
+				"AvatarUrl string `json:\"avatar_url\"",
+				"Type int32 `json:\"type\"",
+				"X1StPlace int32 `json:\"1_st_place\" gorm:\"column:1st_place",
+			},
+		},
+		{
+			name: "initialisms",
+			sql:  "CREATE TABLE api_keys (id int, user_id int, avatar_url varchar(255))",
+			setup: func(ddlParser *parser.DDLParser) {
+				ddlParser.Naming = naming.NewNamer(naming.CommonInitialisms, false)
+			},
+			want: []string{
+				"type APIKeys struct{",
+				"ID int32 `json:\"id\"",
+				"UserID int32 `json:\"user_id\"",
+				"AvatarURL string `json:\"avatar_url\"",
+			},
+		},
+		{
+			name: "pinyin",
+			sql:  "CREATE TABLE `用户表` (`用户名` varchar(64), `user名称` int)",
+			setup: func(ddlParser *parser.DDLParser) {
+				ddlParser.Naming = naming.NewNamer(nil, true)
+			},
+			want: []string{
+				"type YongHuBiao struct{",
+				"YongHuMing string `json:\"用户名\" gorm:\"column:用户名",
+				"UserMingCheng int32 `json:\"user名称\"",
+				`func (YongHuBiao) TableName() string { return "用户表" }`,
+			},
+		},
+		{
+			name: "letters outside ASCII",
+			sql:  "CREATE TABLE `用户表` (`user名称` int)",
+			want: []string{
+				"type X用户表 struct{",
+				"User名称 int32 `json:\"user名称\"",
+			},
+		},
+	})
+}
+
+func TestTableNameOutput(t *testing.T) {
+	setup := withConfig(t, `
+table_names:
+  - match: '^tb_'
+    strip: '^tb_|_[0-9]+$'
+    singular: true
+    suffix: PO
+  - strip: '^t_'
+    singular: true
+tables:
+  users:
+    name: Account
+`)
+	sql := "CREATE TABLE t_admin_roles (id int); CREATE TABLE tb_order_items_00 (id int); CREATE TABLE users (id int);"
+	runOutputTests(t, []outputTest{
+		{
+			name:  "table_names rules",
+			sql:   sql,
+			setup: setup,
+			want: []string{
+				"type AdminRole struct{",
+				`func (AdminRole) TableName() string { return "t_admin_roles" }`,
+				"type OrderItemPO struct{",
+				`func (OrderItemPO) TableName() string { return "tb_order_items_00" }`,
+				"type Account struct{",
+				`func (Account) TableName() string { return "users" }`,
+			},
+		},
+	})
+
+	ddlParser := parser.New("test.sql", "", "test")
+	setup(ddlParser)
+	if err := ddlParser.ParseInputs(parser.Input{File: "test.sql", SQL: sql}); err != nil {
+		t.Fatal(err)
+	}
+	want := []parser.Renaming{
+		{TableName: "t_admin_roles", StructName: "AdminRole", Rule: `table_names.1: strip "^t_", singular`},
+		{TableName: "tb_order_items_00", StructName: "OrderItemPO", Rule: `table_names.0: match "^tb_", strip "^tb_|_[0-9]+$", singular, suffix PO`},
+		{TableName: "users", StructName: "Account", Rule: "tables.users.name"},
+	}
+	if !reflect.DeepEqual(ddlParser.Renamings, want) {
+		t.Errorf("got renamings %+v, want %+v", ddlParser.Renamings, want)
+	}
+}
@@ -2,52 +2,149 @@ package parser
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"path"
 	"regexp"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser"
 	"github.com/pingcap/parser/ast"
-	"github.com/pingcap/parser/mysql"
-	"github.com/pingcap/parser/types"
+	"github.com/pingcap/parser/format"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/config"
+	"github.com/Sterrenhemel/ddl2struct/pkg/naming"
+	tidbtypes "github.com/Sterrenhemel/ddl2struct/pkg/types"
 )
 
 var (
-	goFileRegex = regexp.MustCompile("([a-zA-Z0-9_]*\\.go)")
+	goFileRegex    = regexp.MustCompile("([a-zA-Z0-9_]*\\.go)")
+	qualifierRegex = regexp.MustCompile("([a-zA-Z_][a-zA-Z0-9_]*)\\.")
 )
 
 type DDLParser struct {
 	FileTables  map[string]map[string]*Table // fileName -> TableName -> Table
 	FileImports map[string]map[string]string // fileName -> alias -> importName
 	Index       map[string]Indexes
-	InputFile   string
+	Warnings    []string // problems that do not prevent generation
+	InputFile   string   // input being parsed, see ParseInputs
 	OutputFile  string
 	IsDir       bool
+	Nullable    NullableStrategy
+	// OutputName is the name, without .go, of the file generated in an
+	// output directory for the tables whose comment names no file. It
+	// defaults to the name of InputFile.
+	OutputName string
+	// TinyIntAsBool maps TINYINT(1) and BOOLEAN columns to bool.
+	TinyIntAsBool bool
+	// EnumTypes generates a named Go type for every ENUM and SET column.
+	EnumTypes bool
+	// Constructors computes the DefaultExpr of columns, for templates that
+	// generate constructors.
+	Constructors bool
+	// Validate computes the ValidateType of columns, for templates that
+	// generate Validate methods.
+	Validate bool
+	// Tags are the tag families of the fields, see SetTags.
+	Tags []TagFamily
+	// Naming derives the Go names of tables and columns the config does not
+	// name.
+	Naming naming.Namer
+	// Collisions tells what to do with Go names declared twice.
+	Collisions CollisionStrategy
+	// Renamings tell how the struct of every table was named, in table name
+	// order.
+	Renamings   []Renaming
 	packageName string
-	err         error
-	p           *parser.Parser
+
+	config           *config.Config // table and column settings, see SetConfig
+	genericNullType  string
+	genericNullValue genericNullValue  // of genericNullType, nil when unknown
+	inputFiles       []string          // files of the inputs of the session, in order
+	typeMap          map[string]string // type key -> Go type, see typeKey
+	typeImports      map[string]string // alias -> importName of the packages generated types refer to
+	err              error
+	p                *parser.Parser
+}
+
+// Input is a DDL file of a parse session.
+type Input struct {
+	File       string // file name, reported in errors and generated headers
+	OutputName string // see DDLParser.OutputName
+	SQL        string
 }
 
+// Parse parses the DDL of InputFile, replacing the tables parsed before.
 func (parser *DDLParser) Parse(sql string) error {
-	nodes, _, err := parser.p.Parse(sql, "", "")
-	if err != nil {
-		return errors.Wrap(err, "sql parsing error")
-	}
+	return parser.ParseInputs(Input{File: parser.InputFile, OutputName: parser.OutputName, SQL: sql})
+}
+
+// ParseInputs parses inputs in order as one session, replacing the tables
+// parsed before: a statement may alter a table created by an earlier input,
+// and the tables of inputs routed to the same output file are generated
+// together. Creating a table that an earlier input created is an error,
+// unless with IF NOT EXISTS.
+func (parser *DDLParser) ParseInputs(inputs ...Input) error {
 	parser.FileTables = make(map[string]map[string]*Table)
 	parser.FileImports = make(map[string]map[string]string)
 	parser.Index = make(map[string]Indexes)
+	parser.Warnings = nil
+	parser.inputFiles = nil
 
-	for _, node := range nodes {
-		node.Accept(parser)
-		if parser.err != nil {
-			return errors.Wrap(err, "sql parsing error")
+	for _, input := range inputs {
+		nodes, _, err := parser.p.Parse(input.SQL, "", "")
+		if err != nil {
+			return errors.Wrapf(err, "%s: sql parsing error", input.File)
 		}
+		parser.InputFile, parser.OutputName = input.File, input.OutputName
+		parser.inputFiles = append(parser.inputFiles, input.File)
+		for _, node := range nodes {
+			node.Accept(parser)
+			if parser.err != nil {
+				return errors.Annotatef(parser.err, "%s: sql parsing error in %q", input.File, strings.TrimSpace(node.Text()))
+			}
+		}
+	}
+	parser.excludeTables()
+	if err := parser.checkPackages(); err != nil {
+		return err
+	}
+	if err := parser.resolveTypes(); err != nil {
+		return err
 	}
+	parser.collectImports()
+	parser.collectIndexes()
+	parser.checkReferences()
 
 	return nil
 }
 
+// Inputs returns the files of the inputs of the session, in the order they
+// were parsed.
+func (parser *DDLParser) Inputs() []string {
+	return parser.inputFiles
+}
+
+// FileInputs returns the inputs that created or changed the tables of the
+// output file fileName, in the order they were parsed.
+func (parser *DDLParser) FileInputs(fileName string) []string {
+	contributed := make(map[string]bool)
+	for _, table := range parser.FileTables[fileName] {
+		for _, input := range table.InputFiles {
+			contributed[input] = true
+		}
+	}
+	var inputs []string
+	for _, input := range parser.inputFiles {
+		if contributed[input] {
+			inputs = append(inputs, input)
+			delete(contributed, input)
+		}
+	}
+	return inputs
+}
+
 //func (parser DDLParser) ToStructs(withTag bool) (fileContentMap map[string][]byte, err error) {
 //	fileContentMap = make(map[string][]byte)
 //	var builder strings.Builder
@@ -70,6 +167,16 @@ func (parser *DDLParser) Enter(n ast.Node) (node ast.Node, skipChildren bool) {
 		parser.err = parser.parseCreateTableStmt(n)
 	case *ast.CreateIndexStmt:
 		parser.err = parser.parseCreateIndexStmt(n)
+	case *ast.AlterTableStmt:
+		parser.err = parser.parseAlterTableStmt(n)
+	case *ast.DropIndexStmt:
+		parser.err = parser.parseDropIndexStmt(n)
+	case *ast.DropTableStmt:
+		parser.err = parser.parseDropTableStmt(n)
+	case *ast.RenameTableStmt:
+		parser.err = parser.parseRenameTableStmt(n)
+	case *ast.TruncateTableStmt:
+		// TRUNCATE only removes rows, the table shape is unchanged.
 	}
 	return n, true
 }
@@ -81,51 +188,191 @@ func (parser *DDLParser) Leave(n ast.Node) (node ast.Node, ok bool) {
 func (parser *DDLParser) parseCreateTableStmt(stmt *ast.CreateTableStmt) error {
 	fileName, tableComment := parser.parseOutput(stmt)
 	tableName := stmt.Table.Name.String()
-	if parser.FileImports[fileName] == nil {
-		parser.FileImports[fileName] = make(map[string]string)
-	}
-	if parser.FileTables[fileName] == nil {
-		parser.FileTables[fileName] = make(map[string]*Table)
-	}
-
-	if _, ok := parser.FileTables[fileName][tableName]; ok {
+	fileName, pkg := parser.configOutput(tableName, fileName)
+	if _, existing := parser.findTable(tableName); existing != nil {
+		if stmt.IfNotExists {
+			return nil
+		}
+		if existing.InputFile != parser.InputFile {
+			return errors.Errorf("duplicate table name :%s, already created in %s", tableName, existing.InputFile)
+		}
 		return errors.Errorf("duplicate table name :%s", tableName)
 	} else {
+		if parser.FileTables[fileName] == nil {
+			parser.FileTables[fileName] = make(map[string]*Table)
+		}
 		table := &Table{
 			TableName:    tableName,
 			TableComment: tableComment,
+			InputFile:    parser.InputFile,
+			InputFiles:   []string{parser.InputFile},
+			Package:      pkg,
 			Columns:      []Column{},
 		}
 		parser.FileTables[fileName][tableName] = table
 		for _, col := range stmt.Cols {
-			var colComment string
-			for _, option := range col.Options {
-				if option.Tp == ast.ColumnOptionComment {
-					if option.StrValue != "" {
-						colComment = option.StrValue
-					} else if option.Text() != "" {
-						colComment = option.Text()
-					} else {
-						var buf bytes.Buffer
-						option.Expr.Format(&buf)
-						colComment = buf.String()
-					}
+			tableColumn, err := parser.newColumn(col)
+			if err != nil {
+				return err
+			}
+			table.Columns = append(table.Columns, tableColumn)
+		}
+		for _, col := range stmt.Cols {
+			if err := parser.addColumnIndexes(table, col); err != nil {
+				return err
+			}
+			if err := parser.addColumnChecks(table, col); err != nil {
+				return err
+			}
+		}
+		for _, constraint := range stmt.Constraints {
+			if err := parser.addConstraint(table, constraint); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
 
-					break
-				}
+func (parser *DDLParser) newColumn(col *ast.ColumnDef) (Column, error) {
+	if isBinaryType(col.Tp) {
+		tidbtypes.SetBinChsClnFlag(col.Tp)
+	}
+	column := Column{
+		Name:      col.Name.Name.String(),
+		BaseType:  parser.getColumnType(col.Tp),
+		FieldType: col.Tp,
+	}
+	for _, option := range col.Options {
+		switch option.Tp {
+		case ast.ColumnOptionComment:
+			if option.StrValue != "" {
+				column.Comment = option.StrValue
+			} else if value, ok := option.Expr.(ast.ValueExpr); ok {
+				column.Comment = value.GetDatumString()
+			} else if option.Text() != "" {
+				column.Comment = option.Text()
+			} else {
+				var buf bytes.Buffer
+				option.Expr.Format(&buf)
+				column.Comment = buf.String()
 			}
-			tableColumn := Column{
-				Name:    col.Name.Name.String(),
-				Type:    parser.getColumnType(col.Tp),
-				Comment: colComment,
+		case ast.ColumnOptionNotNull, ast.ColumnOptionPrimaryKey:
+			column.NotNull = true
+		case ast.ColumnOptionNull:
+			column.NotNull = false
+		case ast.ColumnOptionAutoIncrement:
+			column.AutoIncrement = true
+		case ast.ColumnOptionDefaultValue:
+			if err := parser.evalDefault(&column, option.Expr); err != nil {
+				return column, err
 			}
-			parser.addImport(fileName, tableColumn)
-			table.Columns = append(table.Columns, tableColumn)
+		case ast.ColumnOptionOnUpdate:
+			column.OnUpdate = restoreNode(option.Expr)
+		case ast.ColumnOptionGenerated:
+			if err := setGenerated(&column, option); err != nil {
+				return column, err
+			}
+		case ast.ColumnOptionCollate:
+			if col.Tp.Collate == "" {
+				col.Tp.Collate = option.StrValue
+			}
+		}
+	}
+	if err := parser.applyAnnotations(&column); err != nil {
+		return column, err
+	}
+	return column, nil
+}
+
+// restoreNode returns the SQL text of node, as written back by the parser.
+// Names are left unquoted so that the text can be embedded in struct tags.
+func restoreNode(node ast.Node) string {
+	var sb strings.Builder
+	flags := format.RestoreStringSingleQuotes | format.RestoreKeyWordUppercase
+	if err := node.Restore(format.NewRestoreCtx(flags, &sb)); err != nil {
+		return ""
+	}
+	return sb.String()
+}
+
+func (parser *DDLParser) warnf(format string, args ...interface{}) {
+	parser.Warnings = append(parser.Warnings, fmt.Sprintf(format, args...))
+}
+
+// findTable returns the output file and the table registered under tableName.
+func (parser *DDLParser) findTable(tableName string) (string, *Table) {
+	for fileName, tables := range parser.FileTables {
+		if table, ok := tables[tableName]; ok {
+			return fileName, table
 		}
 	}
+	return "", nil
+}
+
+// touchTable records that the input being parsed changes table.
+func (parser *DDLParser) touchTable(table *Table) {
+	if n := len(table.InputFiles); n == 0 || table.InputFiles[n-1] != parser.InputFile {
+		table.InputFiles = append(table.InputFiles, parser.InputFile)
+	}
+}
+
+func (parser *DDLParser) parseDropTableStmt(stmt *ast.DropTableStmt) error {
+	if stmt.IsView {
+		return nil
+	}
+	for _, t := range stmt.Tables {
+		tableName := t.Name.String()
+		fileName, table := parser.findTable(tableName)
+		if table == nil {
+			if stmt.IfExists {
+				continue
+			}
+			return errors.Errorf("drop unknown table :%s", tableName)
+		}
+		parser.removeTable(fileName, tableName)
+	}
 	return nil
 }
 
+func (parser *DDLParser) parseRenameTableStmt(stmt *ast.RenameTableStmt) error {
+	for _, t2t := range stmt.TableToTables {
+		if err := parser.renameTable(t2t.OldTable.Name.String(), t2t.NewTable.Name.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameTable moves a table under its new name, keeping it in the same output file.
+func (parser *DDLParser) renameTable(oldName, newName string) error {
+	fileName, table := parser.findTable(oldName)
+	if table == nil {
+		return errors.Errorf("rename unknown table :%s", oldName)
+	}
+	if oldName == newName {
+		return nil
+	}
+	if _, other := parser.findTable(newName); other != nil {
+		return errors.Errorf("duplicate table name :%s", newName)
+	}
+	parser.touchTable(table)
+	delete(parser.FileTables[fileName], oldName)
+	table.TableName = newName
+	parser.FileTables[fileName][newName] = table
+	parser.renameReferencedTable(oldName, newName)
+	return nil
+}
+
+// removeTable forgets a dropped table. Files left without tables are removed
+// too, so that nothing is generated for them.
+func (parser *DDLParser) removeTable(fileName, tableName string) {
+	delete(parser.FileTables[fileName], tableName)
+	if len(parser.FileTables[fileName]) == 0 {
+		delete(parser.FileTables, fileName)
+	}
+}
+
 func (parser *DDLParser) parseOutput(stmt *ast.CreateTableStmt) (fileName string, tableComment string) {
 	s, err := os.Stat(parser.OutputFile)
 	if err != nil {
@@ -141,10 +388,12 @@ func (parser *DDLParser) parseOutput(stmt *ast.CreateTableStmt) (fileName string
 				}
 			}
 			if fileName == "" {
-				fileName = path.Base(parser.InputFile)
-				fileSuffix := path.Ext(parser.InputFile)
-				filePrefix := fileName[0 : len(fileName)-len(fileSuffix)]
-				if fileName != "" {
+				filePrefix := parser.OutputName
+				if filePrefix == "" {
+					fileName = path.Base(parser.InputFile)
+					filePrefix = fileName[0 : len(fileName)-len(path.Ext(fileName))]
+				}
+				if filePrefix != "" {
 					fileName = path.Join(parser.OutputFile, filePrefix+".go")
 				} else {
 					fileName = path.Join(parser.OutputFile, "tables.go")
@@ -159,44 +408,151 @@ func (parser *DDLParser) parseOutput(stmt *ast.CreateTableStmt) (fileName string
 	return
 }
 
-func (parser *DDLParser) addImport(fileName string, column Column) {
-	switch column.Type {
-	case "time.Time":
-		parser.FileImports[fileName]["time"] = "time"
+// resolveTypes sets the final Go type and default of every column once NOT
+// NULL and primary keys are known. Primary key columns are NOT NULL, as in MySQL.
+func (parser *DDLParser) resolveTypes() error {
+	tables := parser.outputTables()
+	parser.Renamings = nil
+	packages := make(map[string]nameSpace)
+	structs := make(map[*Table]nameSpace, len(tables))
+	// Struct names are declared first, so that enum types yield to them.
+	for _, t := range tables {
+		pkg := packages[packageOf(t.fileName)]
+		if pkg == nil {
+			pkg = make(nameSpace)
+			packages[packageOf(t.fileName)] = pkg
+		}
+		fields, err := parser.resolveNames(t.Table, pkg)
+		if err != nil {
+			return err
+		}
+		structs[t.Table] = fields
 	}
+	for _, t := range tables {
+		table := t.Table
+		if parser.withTags(table) {
+			table.Tags = parser.Tags
+		}
+		primary := table.Indexes.Primary()
+		for i := range table.Columns {
+			column := &table.Columns[i]
+			if primary != nil && primary.HasColumn(column.Name) {
+				column.NotNull = true
+			}
+			overridden, strategy := parser.applyColumnConfig(table, column)
+			column.Enum = nil
+			if !overridden {
+				enum, err := parser.enumOf(table, *column, packages[packageOf(t.fileName)])
+				if err != nil {
+					return err
+				}
+				column.Enum = enum
+			}
+			if column.Enum != nil {
+				column.BaseType = column.Enum.TypeName
+			}
+			column.Type = column.BaseType
+			if !column.NotNull {
+				column.Type = parser.nullableType(strategy, column.BaseType)
+			}
+			if err := resolveDefault(column); err != nil {
+				return errors.Annotatef(err, "table %s", table.TableName)
+			}
+			if parser.Constructors {
+				column.DefaultExpr = parser.defaultExpr(*column)
+			}
+			column.ValidateType = ""
+			if parser.Validate {
+				column.ValidateType = validateType(*column)
+			}
+		}
+		for i := range table.Columns {
+			column := &table.Columns[i]
+			resolveJSONExtract(table, column)
+			if column.JSONExtract != nil && column.JSONExtract.Accessor != "" {
+				accessor, err := parser.declare(structs[table], column.JSONExtract.Accessor, "accessor of column "+column.Name+" of table "+table.TableName)
+				if err != nil {
+					return err
+				}
+				column.JSONExtract.Accessor = accessor
+			}
+		}
+		parser.resolveChecks(table)
+		if err := table.checkTagNames(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (parser *DDLParser) parseCreateIndexStmt(stmt *ast.CreateIndexStmt) error {
-	return nil
+// collectImports computes the imports of every output file from the tables
+// left once all statements have been applied.
+func (parser *DDLParser) collectImports() {
+	for fileName, tables := range parser.FileTables {
+		parser.FileImports[fileName] = make(map[string]string)
+		for _, table := range tables {
+			for alias, importName := range parser.TableImports(table) {
+				parser.FileImports[fileName][alias] = importName
+			}
+		}
+	}
+}
+
+// TableImports returns the imports the code generated for table needs, by
+// alias.
+func (parser *DDLParser) TableImports(table *Table) map[string]string {
+	imports := make(map[string]string)
+	for _, column := range table.Columns {
+		parser.addImport(imports, column)
+		if column.Enum != nil {
+			imports["driver"] = "database/sql/driver"
+			imports["dbtypes"] = DBTypesImport
+		}
+	}
+	if table.HasValidate() {
+		imports["dbtypes"] = DBTypesImport
+	}
+	return imports
 }
 
-func (parser *DDLParser) getColumnType(fieldType *types.FieldType) string {
-	fieldType.EvalType()
-	switch fieldType.Tp {
-	case mysql.TypeTiny:
-		return "int8"
-	case mysql.TypeShort, mysql.TypeInt24, mysql.TypeYear:
-		return "int"
-	case mysql.TypeLong, mysql.TypeLonglong, mysql.TypeBit:
-		return "int64"
-	case mysql.TypeFloat:
-		return "float32"
-	case mysql.TypeDouble, mysql.TypeNewDecimal:
-		return "float64"
-	case mysql.TypeDate, mysql.TypeDatetime:
-		return "time.Time"
-	case mysql.TypeTimestamp:
-		return "time.Time"
-	default:
-		return "string"
+// collectIndexes exposes the indexes of every table left once all statements
+// have been applied, keyed by table name.
+func (parser *DDLParser) collectIndexes() {
+	for _, tables := range parser.FileTables {
+		for tableName, table := range tables {
+			parser.Index[tableName] = table.Indexes
+		}
+	}
+}
+
+func (parser *DDLParser) addImport(imports map[string]string, column Column) {
+	for _, match := range qualifierRegex.FindAllStringSubmatch(column.Type+" "+column.DefaultExpr, -1) {
+		if importName, ok := parser.typeImports[match[1]]; ok {
+			imports[match[1]] = importName
+		}
 	}
 }
 
 func New(input string, output string, packageName string) *DDLParser {
-	return &DDLParser{
+	ddlParser := &DDLParser{
 		p:           parser.New(),
 		InputFile:   input,
 		OutputFile:  output,
+		Nullable:    NullableNone,
+		Collisions:  CollisionError,
+		EnumTypes:   true,
 		packageName: packageName,
+		typeMap:     make(map[string]string, len(DefaultTypeMap)),
+		typeImports: map[string]string{
+			"time":    "time",
+			"sql":     "database/sql",
+			"dbtypes": DBTypesImport,
+		},
+	}
+	for key, goType := range DefaultTypeMap {
+		ddlParser.typeMap[key] = goType
 	}
+	_ = ddlParser.SetGenericNullType(DefaultGenericNullType)
+	_ = ddlParser.SetTags(DefaultTags)
+	return ddlParser
 }
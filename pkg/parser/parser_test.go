@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// parseSQL parses sql as the single input of a session of parser, a new
+// parser when nil.
+func parseSQL(t *testing.T, parser *DDLParser, sql string) *DDLParser {
+	t.Helper()
+	if parser == nil {
+		parser = New("test.sql", "", "test")
+	}
+	if err := parser.ParseInputs(Input{File: "test.sql", SQL: sql}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return parser
+}
+
+// tableOf returns the table named name of parser.
+func tableOf(t *testing.T, parser *DDLParser, name string) *Table {
+	t.Helper()
+	_, table := parser.findTable(name)
+	if table == nil {
+		t.Fatalf("no table %s", name)
+	}
+	return table
+}
+
+// columnOf returns the column named name of table.
+func columnOf(t *testing.T, table *Table, name string) *Column {
+	t.Helper()
+	for i := range table.Columns {
+		if table.Columns[i].Name == name {
+			return &table.Columns[i]
+		}
+	}
+	t.Fatalf("no column %s in table %s", name, table.TableName)
+	return nil
+}
+
+func TestInputFiles(t *testing.T) {
+	parser := New("", "", "test")
+	err := parser.ParseInputs(
+		Input{File: "a.sql", SQL: "CREATE TABLE a (id int); CREATE TABLE b (id int);"},
+		Input{File: "b.sql", SQL: "ALTER TABLE a ADD COLUMN name varchar(32);"},
+		Input{File: "c.sql", SQL: "CREATE INDEX idx_id ON b (id); RENAME TABLE b TO c;"},
+		Input{File: "d.sql", SQL: "ALTER TABLE a DROP COLUMN name;"},
+	)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	for _, test := range []struct {
+		table  string
+		inputs []string
+	}{
+		{"a", []string{"a.sql", "b.sql", "d.sql"}},
+		{"c", []string{"a.sql", "c.sql"}},
+	} {
+		if got := tableOf(t, parser, test.table).InputFiles; !reflect.DeepEqual(got, test.inputs) {
+			t.Errorf("%s: got inputs %v, want %v", test.table, got, test.inputs)
+		}
+	}
+	fileName, _ := parser.findTable("a")
+	if got, want := parser.FileInputs(fileName), []string{"a.sql", "b.sql", "c.sql", "d.sql"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got file inputs %v, want %v", got, want)
+	}
+}
+
+func TestDuplicateTable(t *testing.T) {
+	err := New("", "", "test").ParseInputs(
+		Input{File: "a.sql", SQL: "CREATE TABLE a (id int);"},
+		Input{File: "b.sql", SQL: "CREATE TABLE a (id int);"},
+	)
+	if err == nil || !strings.Contains(err.Error(), "duplicate table name :a, already created in a.sql") {
+		t.Errorf("got %v, want a duplicate table error", err)
+	}
+}
+
+func TestBitType(t *testing.T) {
+	parser := New("", "", "test")
+	if err := parser.SetType("bit(1)", "bool"); err != nil {
+		t.Fatal(err)
+	}
+	table := tableOf(t, parseSQL(t, parser, "CREATE TABLE t (flag bit(1), mask bit(8), wide bit(64))"), "t")
+	for _, test := range []struct {
+		column string
+		goType string
+	}{
+		{"flag", "bool"},
+		{"mask", "dbtypes.Bit"},
+		{"wide", "dbtypes.Bit"},
+	} {
+		if got := columnOf(t, table, test.column).Type; got != test.goType {
+			t.Errorf("%s: got %s, want %s", test.column, got, test.goType)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package parser
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/naming"
+)
+
+// Relationship is a foreign key resolved against the tables of a session.
+type Relationship struct {
+	Table      *Table // referencing table
+	ForeignKey *ForeignKey
+	RefTable   *Table // referenced table; nil when it is not among the parsed tables
+}
+
+// Relationships resolves the foreign keys of the tables of the session, so
+// that a table may reference one defined in another input file. They are
+// ordered by table and foreign key name.
+func (parser *DDLParser) Relationships() []Relationship {
+	tables := make(map[string]*Table)
+	for _, fileTables := range parser.FileTables {
+		for tableName, table := range fileTables {
+			tables[tableName] = table
+		}
+	}
+	var relationships []Relationship
+	for _, table := range tables {
+		for _, fk := range table.ForeignKeys {
+			relationships = append(relationships, Relationship{
+				Table:      table,
+				ForeignKey: fk,
+				RefTable:   tables[fk.RefTable],
+			})
+		}
+	}
+	sort.Slice(relationships, func(i, j int) bool {
+		a, b := relationships[i], relationships[j]
+		if a.Table.TableName != b.Table.TableName {
+			return a.Table.TableName < b.Table.TableName
+		}
+		return a.ForeignKey.Name < b.ForeignKey.Name
+	})
+	return relationships
+}
+
+// checkReferences warns of the foreign keys referencing a table of no input,
+// which get no association and which templates see with a nil RefTable.
+func (parser *DDLParser) checkReferences() {
+	for _, relationship := range parser.Relationships() {
+		if relationship.RefTable == nil {
+			parser.warnf("table %s: foreign key %s references table %s, which no input creates",
+				relationship.Table.TableName, relationship.ForeignKey.Name, relationship.ForeignKey.RefTable)
+		}
+	}
+}
+
+type AssociationKind string
+
+const (
+	AssociationBelongsTo AssociationKind = "belongs_to"
+	AssociationHasOne    AssociationKind = "has_one"
+	AssociationHasMany   AssociationKind = "has_many"
+)
+
+// Association is a GORM association field generated from a foreign key: a
+// belongs-to on the referencing table, and a has-one or has-many on the
+// referenced table.
+type Association struct {
+	Name       string // field name
+	Kind       AssociationKind
+	Table      string // associated table
+	Struct     string // Go type of the associated table
+	ForeignKey *ForeignKey
+
+	referencing, referenced *Table // tables of the foreign key
+}
+
+// Type returns the Go type of the field.
+func (association *Association) Type() string {
+	if association.Kind == AssociationHasMany {
+		return "[]" + association.Struct
+	}
+	return "*" + association.Struct
+}
+
+// GormTag returns the gorm tag of the field. Both sides name the fields of
+// the referencing struct as foreignKey and those of the referenced one as
+// references; the referential actions go on the belongs-to side, which owns
+// the constraint.
+func (association *Association) GormTag() string {
+	fk := association.ForeignKey
+	settings := []string{
+		"foreignKey:" + association.referencing.fieldNames(fk.Columns),
+		"references:" + association.referenced.fieldNames(fk.RefColumns),
+	}
+	if association.Kind == AssociationBelongsTo {
+		var actions []string
+		if fk.OnDelete != "" {
+			actions = append(actions, "OnDelete:"+fk.OnDelete)
+		}
+		if fk.OnUpdate != "" {
+			actions = append(actions, "OnUpdate:"+fk.OnUpdate)
+		}
+		if len(actions) > 0 {
+			settings = append(settings, "constraint:"+strings.Join(actions, ","))
+		}
+	}
+	return strings.Join(settings, ";")
+}
+
+// fieldNames returns the Go fields of columns, comma separated.
+func (table *Table) fieldNames(columns []string) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = naming.Namer{}.Exported(column)
+		if pos := table.Columns.Find(column); pos >= 0 {
+			names[i] = table.Columns[pos].FieldName
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// AddAssociations sets the Associations of the tables linked by
+// relationships, replacing those set before. Relationships whose referenced
+// table is unknown or in another package are skipped. Field names are derived from the foreign key
+// column, e.g. author_id gives Author, or from the associated table, and are
+// numbered when they would collide with another field.
+func (parser *DDLParser) AddAssociations(relationships []Relationship) {
+	for _, relationship := range relationships {
+		relationship.Table.Associations = nil
+		if relationship.RefTable != nil {
+			relationship.RefTable.Associations = nil
+		}
+	}
+	for _, relationship := range relationships {
+		if relationship.RefTable == nil || relationship.RefTable.Package != relationship.Table.Package {
+			continue
+		}
+		table, fk, refTable := relationship.Table, relationship.ForeignKey, relationship.RefTable
+
+		name := refTable.TableName
+		if len(fk.Columns) == 1 {
+			column := strings.ToLower(fk.Columns[0])
+			if trimmed := strings.TrimSuffix(column, "_id"); trimmed != column && trimmed != "" {
+				name = fk.Columns[0][:len(trimmed)]
+			}
+		}
+		table.addAssociation(&Association{
+			Name:        parser.Naming.Exported(name),
+			Kind:        AssociationBelongsTo,
+			Table:       refTable.TableName,
+			Struct:      refTable.StructName,
+			ForeignKey:  fk,
+			referencing: table,
+			referenced:  refTable,
+		})
+
+		association := &Association{
+			Name:        Pluralize(table.StructName),
+			Kind:        AssociationHasMany,
+			Table:       table.TableName,
+			Struct:      table.StructName,
+			ForeignKey:  fk,
+			referencing: table,
+			referenced:  refTable,
+		}
+		if table.hasUniqueKey(fk.Columns) {
+			association.Name = table.StructName
+			association.Kind = AssociationHasOne
+		}
+		refTable.addAssociation(association)
+	}
+}
+
+// addAssociation appends association, numbering its name when a column, a
+// method or another association of the table already has it.
+func (table *Table) addAssociation(association *Association) {
+	taken := map[string]bool{"TableName": true, "Validate": table.HasValidate()}
+	for _, column := range table.Columns {
+		taken[column.FieldName] = true
+		if column.JSONExtract != nil && column.JSONExtract.Accessor != "" {
+			taken[column.JSONExtract.Accessor] = true
+		}
+	}
+	for _, other := range table.Associations {
+		taken[other.Name] = true
+	}
+	name := association.Name
+	for n := 2; taken[name]; n++ {
+		name = association.Name + strconv.Itoa(n)
+	}
+	association.Name = name
+	table.Associations = append(table.Associations, association)
+}
+
+// hasUniqueKey reports whether columns, in any order, are exactly the columns
+// of a primary or unique key without prefix lengths.
+func (table *Table) hasUniqueKey(columns []string) bool {
+	for _, index := range table.Indexes {
+		if index.Kind != IndexKindPrimary && index.Kind != IndexKindUnique || len(index.Columns) != len(columns) {
+			continue
+		}
+		matched := 0
+		for _, indexColumn := range index.Columns {
+			for _, column := range columns {
+				if indexColumn.Length == 0 && strings.EqualFold(indexColumn.Name, column) {
+					matched++
+					break
+				}
+			}
+		}
+		if matched == len(columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pluralize returns the English plural of a CamelCase name, inflecting its
+// last word.
+func Pluralize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case name == "":
+		return name
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	case strings.HasSuffix(lower, "s"):
+		return name
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
+
+// singularExceptions are the plurals Singularize does not inflect by its
+// suffix rules, in lower case.
+var singularExceptions = map[string]string{
+	"children": "child",
+	"data":     "data",
+	"men":      "man",
+	"news":     "news",
+	"people":   "person",
+	"series":   "series",
+	"statuses": "status",
+	"women":    "woman",
+}
+
+// Singularize returns the English singular of a CamelCase or snake_case name,
+// inflecting its last word: order_items gives order_item.
+func Singularize(name string) string {
+	start := strings.LastIndexByte(name, '_') + 1
+	for i := len(name) - 1; i > start; i-- {
+		if unicode.IsUpper(rune(name[i])) && unicode.IsLower(rune(name[i-1])) {
+			start = i
+			break
+		}
+	}
+	word := name[start:]
+	lower := strings.ToLower(word)
+	if singular, ok := singularExceptions[lower]; ok {
+		if word != lower {
+			singular = strings.ToUpper(singular[:1]) + singular[1:]
+		}
+		return name[:start] + singular
+	}
+	switch {
+	case len(lower) > 3 && strings.HasSuffix(lower, "ies"):
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "zes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"), strings.HasSuffix(lower, "is"):
+		return name
+	case len(lower) > 1 && strings.HasSuffix(lower, "s"):
+		return name[:len(name)-1]
+	}
+	return name
+}
@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnknownReferenceWarnings(t *testing.T) {
+	for _, test := range []struct {
+		sql      string
+		warnings []string
+	}{
+		{
+			"CREATE TABLE users (id int PRIMARY KEY); CREATE TABLE orders (id int, user_id int, FOREIGN KEY (user_id) REFERENCES users (id));",
+			nil,
+		},
+		{
+			"CREATE TABLE orders (id int, user_id int, CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id));",
+			[]string{"table orders: foreign key fk_user references table users, which no input creates"},
+		},
+		{
+			"CREATE TABLE orders (id int, user_id int, FOREIGN KEY (user_id) REFERENCES users (id));",
+			[]string{"table orders: foreign key orders_ibfk_1 references table users, which no input creates"},
+		},
+		{
+			"CREATE TABLE users (id int PRIMARY KEY); CREATE TABLE orders (id int, user_id int, FOREIGN KEY (user_id) REFERENCES users (id)); DROP TABLE users;",
+			[]string{"table orders: foreign key orders_ibfk_1 references table users, which no input creates"},
+		},
+	} {
+		parser := parseSQL(t, nil, test.sql)
+		if !reflect.DeepEqual(parser.Warnings, test.warnings) {
+			t.Errorf("%s: got warnings %q, want %q", test.sql, parser.Warnings, test.warnings)
+		}
+	}
+}
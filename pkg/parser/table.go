@@ -1,13 +1,55 @@
 package parser
 
+import (
+	"strings"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/types"
+
+	tidbtypes "github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
 type Table struct {
 	TableName    string
+	StructName   string // Go type name; set once types are resolved
 	TableComment string
+	InputFile    string      // input the table was created in
+	InputFiles   []string    // inputs that created or changed the table, in the order they are read
+	Package      string      // Go package the config generates the table in, when not the default one
+	Tags         []TagFamily // tag families of the fields, none when the config turns tags off
 	Columns      Columns
+	Indexes      Indexes
+	ForeignKeys  ForeignKeys
+	Checks       Checks
+	Associations []*Association // set by AddAssociations
 }
 
 type Columns []Column
 
+// Find returns the position of the column named name, or -1. Column names are
+// case-insensitive as in MySQL.
+func (columns Columns) Find(name string) int {
+	for i, column := range columns {
+		if strings.EqualFold(column.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Insert places column at pos, shifting the following columns to the right.
+func (columns Columns) Insert(pos int, column Column) Columns {
+	columns = append(columns, Column{})
+	copy(columns[pos+1:], columns[pos:])
+	columns[pos] = column
+	return columns
+}
+
+// Remove drops the column at pos.
+func (columns Columns) Remove(pos int) Columns {
+	return append(columns[:pos], columns[pos+1:]...)
+}
+
 //func (columns Columns) ToStructFields(withTag bool) string {
 //	fields := make([]string, 0)
 //	for _, column := range columns {
@@ -17,10 +59,109 @@ type Columns []Column
 //}
 
 type Column struct {
-	Name       string
-	Type       string
-	Comment    string // 注释
-	DefaultVal string
+	Name          string
+	FieldName     string // Go field name; set once types are resolved
+	Type          string // Go type of the field
+	BaseType      string // Go type of the field, ignoring NULL
+	Comment       string // 注释
+	DefaultSQL    string // DEFAULT expression, as SQL text
+	DefaultVal    string // DEFAULT value, as a Go literal of BaseType
+	DefaultNow    bool   // DEFAULT CURRENT_TIMESTAMP
+	DefaultExpr   string // DEFAULT value, as a Go expression of Type; set for constructors only
+	ValidateType  string // dbtypes.ColumnType of the column, as a Go expression; set for validation only
+	OnUpdate      string // ON UPDATE expression, as SQL text
+	NotNull       bool
+	AutoIncrement bool
+	// Generated is the GENERATED ALWAYS AS expression, as SQL text. Generated
+	// columns are read-only.
+	Generated       string
+	GeneratedStored bool         // STORED rather than VIRTUAL
+	JSONExtract     *JSONExtract // set when Generated extracts a path of a JSON column
+	FieldType       *types.FieldType
+	Enum            *Enum             // Go type generated for ENUM and SET columns
+	Annotations     map[string]string // annotations of the comment, see applyAnnotations
+	Serializer      string            // GORM serializer of user-defined types, e.g. "json"
+	Tags            map[string]string // extra struct tags, from the config
+
+	defaultDatum *tidbtypes.Datum // DEFAULT value, converted to FieldType
+}
+
+// SQLType returns the column type as written in DDL, with its length,
+// precision and UNSIGNED flag, e.g. "decimal(10,2) unsigned".
+func (column Column) SQLType() string {
+	if column.FieldType == nil {
+		return ""
+	}
+	if column.FieldType.Tp == mysql.TypeYear && column.FieldType.Flen < 0 {
+		return "year"
+	}
+	return column.FieldType.InfoSchemaStr()
+}
+
+// TypeInfo is the MySQL type of a column, as templates see it.
+type TypeInfo struct {
+	Name      string // type name, e.g. "varchar"
+	Flen      int    // length, display width or precision; -1 when unspecified
+	Decimal   int    // scale or fractional seconds precision; -1 when unspecified
+	Flag      uint   // mysql flags, e.g. mysql.UnsignedFlag
+	Unsigned  bool
+	Zerofill  bool
+	Binary    bool   // holds bytes rather than characters
+	Charset   string // declared charset, "" when inherited from the table
+	Collation string // declared collation, "" when inherited from the table
+	Elems     []string
+}
+
+// TypeInfo returns the details of the column type.
+func (column Column) TypeInfo() TypeInfo {
+	ft := column.FieldType
+	if ft == nil {
+		return TypeInfo{}
+	}
+	return TypeInfo{
+		Name:      types.TypeStr(ft.Tp),
+		Flen:      ft.Flen,
+		Decimal:   ft.Decimal,
+		Flag:      ft.Flag,
+		Unsigned:  mysql.HasUnsignedFlag(ft.Flag),
+		Zerofill:  mysql.HasZerofillFlag(ft.Flag),
+		Binary:    isBinaryType(ft),
+		Charset:   ft.Charset,
+		Collation: ft.Collate,
+		Elems:     ft.Elems,
+	}
+}
+
+// PrimaryKey returns the columns of the primary key, in key order.
+func (table *Table) PrimaryKey() Columns {
+	primary := table.Indexes.Primary()
+	if primary == nil {
+		return nil
+	}
+	var columns Columns
+	for _, name := range primary.ColumnNames() {
+		if pos := table.Columns.Find(name); pos >= 0 {
+			columns = append(columns, table.Columns[pos])
+		}
+	}
+	return columns
+}
+
+// DecimalSize returns the declared precision and scale of a DECIMAL column.
+// Unspecified values take the defaults of a bare DECIMAL.
+func (column Column) DecimalSize() (precision, scale int, ok bool) {
+	if column.FieldType == nil || column.FieldType.Tp != mysql.TypeNewDecimal {
+		return 0, 0, false
+	}
+	precision, scale = column.FieldType.Flen, column.FieldType.Decimal
+	defaultPrecision, defaultScale := mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeNewDecimal)
+	if precision == types.UnspecifiedLength {
+		precision = defaultPrecision
+	}
+	if scale == types.UnspecifiedLength {
+		scale = defaultScale
+	}
+	return precision, scale, true
 }
 
 //func (column Column) ToStructField(withTag bool) string {
@@ -31,7 +172,86 @@ type Column struct {
 //	return fmt.Sprintf("%s %s", strcase.ToCamel(column.Name), column.Type) + tag
 //}
 
-type Indexes []Index
+type Indexes []*Index
+
+// Find returns the position of the index named name, or -1. Index names are
+// case-insensitive as in MySQL.
+func (indexes Indexes) Find(name string) int {
+	for i, index := range indexes {
+		if strings.EqualFold(index.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Primary returns the primary key of the table, or nil.
+func (indexes Indexes) Primary() *Index {
+	for _, index := range indexes {
+		if index.Kind == IndexKindPrimary {
+			return index
+		}
+	}
+	return nil
+}
+
+// OfColumn returns the indexes that contain the column named name.
+func (indexes Indexes) OfColumn(name string) Indexes {
+	var res Indexes
+	for _, index := range indexes {
+		if index.HasColumn(name) {
+			res = append(res, index)
+		}
+	}
+	return res
+}
+
+type IndexKind string
+
+const (
+	IndexKindPrimary  IndexKind = "primary"
+	IndexKindUnique   IndexKind = "unique"
+	IndexKindPlain    IndexKind = "plain"
+	IndexKindFullText IndexKind = "fulltext"
+	IndexKindSpatial  IndexKind = "spatial"
+)
 
 type Index struct {
+	Name      string
+	Kind      IndexKind
+	Columns   []IndexColumn
+	Invisible bool
+	Comment   string
+}
+
+// IndexColumn is one key part of an index. Length is the prefix length, 0 when
+// the whole column is indexed. Expr holds the expression of functional key parts,
+// in which case Name is empty.
+type IndexColumn struct {
+	Name   string
+	Length int
+	Expr   string
+}
+
+func (index *Index) IsUnique() bool {
+	return index.Kind == IndexKindPrimary || index.Kind == IndexKindUnique
+}
+
+// HasColumn reports whether the column named name is a key part of index.
+func (index *Index) HasColumn(name string) bool {
+	for _, column := range index.Columns {
+		if strings.EqualFold(column.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ColumnNames returns the names of the indexed columns, in key order.
+func (index *Index) ColumnNames() []string {
+	names := make([]string, 0, len(index.Columns))
+	for _, column := range index.Columns {
+		names = append(names, column.Name)
+	}
+	return names
 }
@@ -0,0 +1,430 @@
+package parser
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+)
+
+// DefaultTags are the tag families fields have unless SetTags says otherwise.
+const DefaultTags = "json,gorm"
+
+// NameStyle is how a tag spells the column name.
+type NameStyle string
+
+const (
+	NameSnake  NameStyle = "snake"  // user_id
+	NameCamel  NameStyle = "camel"  // userId
+	NamePascal NameStyle = "pascal" // UserId
+	NameKebab  NameStyle = "kebab"  // user-id
+	NameColumn NameStyle = "column" // as in the DDL
+)
+
+var nameStyles = map[NameStyle]func(string) string{
+	NameSnake:  strcase.ToSnake,
+	NameCamel:  strcase.ToLowerCamel,
+	NamePascal: strcase.ToCamel,
+	NameKebab:  strcase.ToKebab,
+	NameColumn: func(name string) string { return name },
+}
+
+// TagFamily is a struct tag every field has, e.g. json or gorm.
+type TagFamily struct {
+	Name    string    // as given to SetTags, e.g. sqlx
+	Key     string    // key of the tag, e.g. db for sqlx
+	Style   NameStyle // spelling of the column name, empty for gorm and validate
+	Options []string  // appended to the value, e.g. omitempty
+}
+
+// tagFamilies are the families SetTags knows, with their default style.
+var tagFamilies = map[string]TagFamily{
+	"json":         {Name: "json", Key: "json", Style: NameSnake},
+	"yaml":         {Name: "yaml", Key: "yaml", Style: NameSnake},
+	"mapstructure": {Name: "mapstructure", Key: "mapstructure", Style: NameSnake},
+	"db":           {Name: "db", Key: "db", Style: NameColumn},
+	"sqlx":         {Name: "sqlx", Key: "db", Style: NameColumn},
+	"bun":          {Name: "bun", Key: "bun", Style: NameColumn},
+	"xorm":         {Name: "xorm", Key: "xorm", Style: NameColumn},
+	"gorm":         {Name: "gorm", Key: "gorm"},
+	"validate":     {Name: "validate", Key: "validate"},
+}
+
+// tagOptions are the options SetTags accepts for each family.
+var tagOptions = map[string][]string{
+	"json":         {"omitempty", "string"},
+	"yaml":         {"omitempty", "flow"},
+	"mapstructure": {"omitempty", "squash", "remain"},
+	"bun":          {"nullzero", "scanonly"},
+	"gorm":         {gormCommentOption},
+}
+
+// SetTags sets the tag families of the fields from a comma separated list of
+// families, each followed by a naming style and options separated by colons,
+// e.g. "json:camel:omitempty,db,gorm,validate". An empty list means no tags.
+// Styles and options a family does not have are errors.
+func (parser *DDLParser) SetTags(spec string) error {
+	var families []TagFamily
+	keys := make(map[string]string)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		family, ok := tagFamilies[parts[0]]
+		if !ok {
+			return errors.Errorf("unknown tag family :%s", parts[0])
+		}
+		if other, ok := keys[family.Key]; ok {
+			return errors.Errorf("tags %s and %s both set the %s tag", other, family.Name, family.Key)
+		}
+		keys[family.Key] = family.Name
+		for _, part := range parts[1:] {
+			switch {
+			case part == "":
+			case nameStyles[NameStyle(part)] != nil:
+				if family.Style == "" {
+					return errors.Errorf("tag %s has no naming style", family.Name)
+				}
+				family.Style = NameStyle(part)
+			case isTagOption(family.Name, part):
+				family.Options = append(family.Options, part)
+			default:
+				return errors.Errorf("unknown naming style or option of tag %s :%s", family.Name, part)
+			}
+		}
+		families = append(families, family)
+	}
+	parser.Tags = families
+	return nil
+}
+
+// isTagOption reports whether option is one of the tagOptions of family.
+func isTagOption(family, option string) bool {
+	for _, o := range tagOptions[family] {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// TagValue returns the value of the tag of family for column, or "" when the
+// field has no such tag.
+func (table *Table) TagValue(family TagFamily, column Column) string {
+	var values []string
+	separator := ","
+	switch family.Name {
+	case "gorm":
+		values, separator = []string{table.gormTag(column, family.hasOption(gormCommentOption))}, ";"
+	case "validate":
+		values = column.validateRules()
+		if len(values) == 0 {
+			return ""
+		}
+	case "bun":
+		values = append([]string{nameStyles[family.Style](column.Name)}, table.bunOptions(column)...)
+	case "xorm":
+		values = append([]string{"'" + nameStyles[family.Style](column.Name) + "'"}, table.xormOptions(column)...)
+		separator = " "
+	default:
+		values = []string{nameStyles[family.Style](column.Name)}
+	}
+	for _, option := range family.Options {
+		if !(family.Name == "gorm" && option == gormCommentOption) {
+			values = append(values, option)
+		}
+	}
+	return strings.Join(values, separator)
+}
+
+// gormCommentOption is the option of the gorm tag family that adds the
+// comments of columns and indexes to the tag.
+const gormCommentOption = "comment"
+
+// hasOption reports whether family has option.
+func (family TagFamily) hasOption(option string) bool {
+	for _, o := range family.Options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// StructTag returns the struct tag of the field of column, without the
+// backquotes: the tags of the families of the table, then the extra tags of
+// the config in key order, then the tags set by annotations of the column.
+// The config and annotations replace the tag a family gives, and an empty
+// annotation removes it, except that validate rules are added to those
+// derived from the column, see mergeValidateRules.
+func (table *Table) StructTag(column Column) string {
+	tags := table.structTag(column)
+	return tags.String()
+}
+
+// structTag returns the struct tag of the field of column, see StructTag.
+func (table *Table) structTag(column Column) structTag {
+	var tags structTag
+	for _, family := range table.Tags {
+		tags.set(family.Key, table.TagValue(family, column))
+	}
+	for _, key := range sortedTagKeys(column.Tags) {
+		tags.override(key, column.Tags[key])
+	}
+	for _, key := range sortedTagKeys(column.Annotations) {
+		if isTagKey(key) {
+			tags.override(key, column.Annotations[key])
+		}
+	}
+	return tags
+}
+
+// checkTagNames fails when the tags of a family give two fields of table the
+// same name, e.g. json:"user_id" for the columns user_id and userId, which
+// the field names alone do not show. Unlike field names, tag names are not
+// numbered under CollisionSuffix, as they are the names the data is read and
+// written with.
+func (table *Table) checkTagNames() error {
+	named := make(map[string]string)
+	for _, column := range table.Columns {
+		what := "column " + column.Name + " of table " + table.TableName
+		tags := table.structTag(column)
+		for _, key := range tags.keys {
+			name := tagName(key, tags.values[key])
+			if name == "" {
+				continue
+			}
+			tag := key + ":" + strconv.Quote(name)
+			if other, ok := named[tag]; ok {
+				return errors.Errorf("%s and %s are both tagged %s, rename one in the config", other, what, tag)
+			}
+			named[tag] = what
+		}
+	}
+	return nil
+}
+
+// tagName returns the name the tag of key gives a field, or "" when the tag
+// is not that of a family naming columns: the column setting of gorm, the
+// quoted name of xorm, the part before the options for the others. The name
+// "-", which leaves the field out, is not a name.
+func tagName(key, value string) string {
+	if !isTagKey(key) {
+		return ""
+	}
+	switch key {
+	case tagFamilies["validate"].Key:
+		return ""
+	case tagFamilies["gorm"].Key:
+		for _, setting := range strings.Split(value, ";") {
+			if strings.HasPrefix(setting, "column:") {
+				return strings.TrimPrefix(setting, "column:")
+			}
+		}
+		return ""
+	case tagFamilies["xorm"].Key:
+		for _, part := range strings.Fields(value) {
+			if len(part) > 2 && strings.HasPrefix(part, "'") && strings.HasSuffix(part, "'") {
+				return part[1 : len(part)-1]
+			}
+		}
+		return ""
+	}
+	if name := strings.SplitN(value, ",", 2)[0]; name != "-" {
+		return name
+	}
+	return ""
+}
+
+// mergeValidateRules returns the validate rules derived followed by those of
+// extra, comma separated. A rule of extra replaces the rule of derived of the
+// same name, as max=64 does max=255, so that an annotation adds checks to
+// those of the column type or narrows them.
+func mergeValidateRules(derived, extra string) string {
+	ruleName := func(rule string) string {
+		return strings.SplitN(rule, "=", 2)[0]
+	}
+	rules := strings.Split(derived, ",")
+	for _, rule := range strings.Split(extra, ",") {
+		replaced := false
+		for i := range rules {
+			if ruleName(rules[i]) == ruleName(rule) {
+				rules[i], replaced = rule, true
+				break
+			}
+		}
+		if !replaced && rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return strings.Join(rules, ",")
+}
+
+// StructTag returns the struct tag of the field of association, without the
+// backquotes, or "" when the fields of its table have no tags. Tags naming
+// columns keep the ORMs from taking the field for one.
+func (association *Association) StructTag() string {
+	table := association.referenced
+	if association.Kind == AssociationBelongsTo {
+		table = association.referencing
+	}
+	var tags structTag
+	for _, family := range table.Tags {
+		switch family.Name {
+		case "gorm":
+			tags.set(family.Key, association.GormTag())
+		case "json", "yaml", "mapstructure":
+			tags.set(family.Key, nameStyles[family.Style](association.Name)+",omitempty")
+		case "db", "sqlx", "bun", "xorm":
+			tags.set(family.Key, "-")
+		}
+	}
+	return tags.String()
+}
+
+// validateRules returns the rules of the validate tag of column, derived from
+// its NOT NULL constraint, length and enum elements.
+func (column Column) validateRules() []string {
+	var rules []string
+	if !column.NotNull {
+		if column.Type != column.BaseType && !strings.HasPrefix(column.Type, "*") {
+			// sql.Null and generic null types are structs the validator
+			// cannot look into.
+			return nil
+		}
+		rules = append(rules, "omitempty")
+	} else if !column.AutoIncrement && column.Generated == "" && column.DefaultSQL == "" && column.BaseType != "bool" {
+		rules = append(rules, "required")
+	}
+	if size := column.size(); size > 0 && column.BaseType == "string" {
+		rules = append(rules, "max="+strconv.Itoa(size))
+	}
+	if column.Enum != nil && !column.Enum.IsSet {
+		values := make([]string, 0, len(column.Enum.Elems))
+		for _, elem := range column.Enum.Elems {
+			if elem.Value == "" || strings.ContainsAny(elem.Value, " \t,|") {
+				values = nil
+				break
+			}
+			values = append(values, elem.Value)
+		}
+		if len(values) > 0 {
+			rules = append(rules, "oneof="+strings.Join(values, " "))
+		}
+	}
+	if len(rules) == 1 && rules[0] == "omitempty" {
+		return nil
+	}
+	return rules
+}
+
+// bunOptions returns the bun settings of column after its name.
+func (table *Table) bunOptions(column Column) []string {
+	var options []string
+	for _, index := range table.Indexes.OfColumn(column.Name) {
+		switch {
+		case index.Kind == IndexKindPrimary:
+			options = append(options, "pk")
+		case index.Kind == IndexKindUnique && len(index.Columns) == 1:
+			options = append(options, "unique")
+		case index.Kind == IndexKindUnique:
+			options = append(options, "unique:"+index.Name)
+		}
+	}
+	if column.AutoIncrement {
+		options = append(options, "autoincrement")
+	}
+	if column.NotNull {
+		options = append(options, "notnull")
+	}
+	return options
+}
+
+// xormOptions returns the xorm settings of column after its name.
+func (table *Table) xormOptions(column Column) []string {
+	var options []string
+	for _, index := range table.Indexes.OfColumn(column.Name) {
+		switch index.Kind {
+		case IndexKindPrimary:
+			options = append(options, "pk")
+		case IndexKindUnique:
+			options = append(options, "unique("+index.Name+")")
+		case IndexKindPlain:
+			options = append(options, "index("+index.Name+")")
+		}
+	}
+	if column.AutoIncrement {
+		options = append(options, "autoincr")
+	}
+	if column.NotNull {
+		options = append(options, "notnull")
+	}
+	if column.FieldType.Tp == mysql.TypeTimestamp && strings.EqualFold(column.OnUpdate, "CURRENT_TIMESTAMP") {
+		options = append(options, "updated")
+	}
+	return options
+}
+
+// isTagKey reports whether key is the key of a tag family, and so an
+// annotation setting that tag.
+func isTagKey(key string) bool {
+	for _, family := range tagFamilies {
+		if family.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedTagKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// structTag is a struct tag being built, whose keys keep the order they are
+// first set in.
+type structTag struct {
+	keys   []string
+	values map[string]string
+}
+
+// set sets the value of key; an empty value removes it.
+func (tag *structTag) set(key, value string) {
+	if tag.values == nil {
+		tag.values = make(map[string]string)
+	}
+	if _, ok := tag.values[key]; !ok {
+		tag.keys = append(tag.keys, key)
+	}
+	tag.values[key] = value
+}
+
+// override sets the value of key over the one a tag family gives: validate
+// rules are merged with the derived ones, other tags are replaced.
+func (tag *structTag) override(key, value string) {
+	if current := tag.values[key]; key == tagFamilies["validate"].Key && value != "" && current != "" {
+		value = mergeValidateRules(current, value)
+	}
+	tag.set(key, value)
+}
+
+// String returns the tag as it goes between the backquotes of a field.
+// Backquotes of the values, which a raw string cannot hold, are escaped.
+func (tag *structTag) String() string {
+	tags := make([]string, 0, len(tag.keys))
+	for _, key := range tag.keys {
+		if value := tag.values[key]; value != "" {
+			tags = append(tags, key+":"+strings.ReplaceAll(strconv.Quote(value), "`", `\x60`))
+		}
+	}
+	return strings.Join(tags, " ")
+}
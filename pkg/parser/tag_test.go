@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const tagTable = `CREATE TABLE users (
+	id bigint AUTO_INCREMENT PRIMARY KEY,
+	user_name varchar(64) NOT NULL,
+	email varchar(128) COMMENT '@validate=email',
+	nick varchar(32) NOT NULL COMMENT '@validate=max=16',
+	password varchar(255) NOT NULL COMMENT 'bcrypt hash @json=- @validate=',
+	status enum('active','banned') NOT NULL DEFAULT 'active',
+	UNIQUE KEY uk_email (email)
+);
+`
+
+// structTagOf returns the struct tag of the column named column of the users
+// table of tagTable, with the tag families of spec.
+func structTagOf(t *testing.T, spec, column string) string {
+	t.Helper()
+	parser := New("test.sql", "", "test")
+	if err := parser.SetTags(spec); err != nil {
+		t.Fatalf("%s: %v", spec, err)
+	}
+	table := tableOf(t, parseSQL(t, parser, tagTable), "users")
+	return table.StructTag(*columnOf(t, table, column))
+}
+
+func TestTagFamilies(t *testing.T) {
+	for _, test := range []struct {
+		spec   string
+		column string
+		tag    string
+	}{
+		{"json", "user_name", `json:"user_name"`},
+		{"json:camel", "user_name", `json:"userName"`},
+		{"json:pascal:omitempty", "user_name", `json:"UserName,omitempty"`},
+		{"yaml:kebab,mapstructure", "user_name", `yaml:"user-name" mapstructure:"user_name"`},
+		{"db", "user_name", `db:"user_name"`},
+		{"sqlx:snake", "user_name", `db:"user_name"`},
+		{"bun", "id", `bun:"id,pk,autoincrement,notnull"`},
+		{"bun:nullzero", "user_name", `bun:"user_name,notnull,nullzero"`},
+		{"xorm", "id", `xorm:"'id' pk autoincr notnull"`},
+		// Annotations add tags of families --tags does not list.
+		{"xorm", "email", `xorm:"'email' unique(uk_email)" validate:"email"`},
+		{"gorm", "user_name", `gorm:"column:user_name;type:varchar(64);size:64;not null"`},
+		{"", "user_name", ``},
+	} {
+		if got := structTagOf(t, test.spec, test.column); got != test.tag {
+			t.Errorf("%s %s: got %s, want %s", test.spec, test.column, got, test.tag)
+		}
+	}
+}
+
+func TestTagFamilyErrors(t *testing.T) {
+	for _, test := range []struct {
+		spec string
+		err  string
+	}{
+		{"jsn", "unknown tag family :jsn"},
+		{"json:camle", "unknown naming style or option of tag json :camle"},
+		{"gorm:snak", "unknown naming style or option of tag gorm :snak"},
+		{"gorm:snake", "tag gorm has no naming style"},
+		{"json:omitempty:nope", "unknown naming style or option of tag json :nope"},
+		{"db,sqlx", "tags db and sqlx both set the db tag"},
+	} {
+		err := New("test.sql", "", "test").SetTags(test.spec)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got %v, want %s", test.spec, err, test.err)
+		}
+	}
+}
+
+func TestTagAnnotations(t *testing.T) {
+	for _, test := range []struct {
+		column string
+		tag    string
+	}{
+		// Annotated rules are added to those of the column type.
+		{"user_name", `json:"user_name" validate:"required,max=64"`},
+		{"email", `json:"email" validate:"omitempty,max=128,email"`},
+		// A rule of the same name replaces the derived one.
+		{"nick", `json:"nick" validate:"required,max=16"`},
+		// An empty annotation removes the tag, - keeps the field out of JSON.
+		{"password", `json:"-"`},
+		{"status", `json:"status" validate:"oneof=active banned"`},
+	} {
+		if got := structTagOf(t, "json,validate", test.column); got != test.tag {
+			t.Errorf("%s: got %s, want %s", test.column, got, test.tag)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/types"
+
+	tidbtypes "github.com/Sterrenhemel/ddl2struct/pkg/types"
+)
+
+// DefaultTypeMap maps MySQL types, as returned by typeKey, to Go types. Types
+// that are not listed map to string.
+var DefaultTypeMap = map[string]string{
+	"tinyint":            "int8",
+	"tinyint unsigned":   "uint8",
+	"smallint":           "int16",
+	"smallint unsigned":  "uint16",
+	"mediumint":          "int32",
+	"mediumint unsigned": "uint32",
+	"int":                "int32",
+	"int unsigned":       "uint32",
+	"bigint":             "int64",
+	"bigint unsigned":    "uint64",
+	"year":               "int16",
+	"bit(1)":             "dbtypes.Bit",
+	"bit":                "dbtypes.Bit",
+	"float":              "float32",
+	"float unsigned":     "float32",
+	"double":             "float64",
+	"double unsigned":    "float64",
+	"decimal":            "dbtypes.Decimal",
+	"decimal unsigned":   "dbtypes.Decimal",
+	"date":               "time.Time",
+	"datetime":           "time.Time",
+	"timestamp":          "time.Time",
+	"time":               "dbtypes.Duration",
+	"binary":             "[]byte",
+	"varbinary":          "[]byte",
+	"tinyblob":           "[]byte",
+	"blob":               "[]byte",
+	"mediumblob":         "[]byte",
+	"longblob":           "[]byte",
+	"geometry":           "[]byte",
+	"json":               "dbtypes.JSON",
+}
+
+// DecimalTypes holds the Go types SetDecimalType accepts for DECIMAL columns,
+// by name.
+var DecimalTypes = map[string]string{
+	"exact":      DBTypesImport + ".Decimal",
+	"shopspring": "github.com/shopspring/decimal.Decimal",
+	"float64":    "float64",
+}
+
+// TimeTypes holds the Go types SetTimeType accepts for DATE, DATETIME and
+// TIMESTAMP columns, by name. MySQLTime holds zero and invalid dates that
+// time.Time cannot.
+var TimeTypes = map[string]string{
+	"time":  "time.Time",
+	"mysql": DBTypesImport + ".MySQLTime",
+}
+
+// typeKey returns the key of fieldType in the type map: the MySQL type name,
+// followed by " unsigned" for unsigned numbers. BIT(1) has its own key, so
+// that a flag may be mapped apart from the bit fields of up to 64 bits. Both
+// map to dbtypes.Bit, which scans the raw bytes drivers return for BIT,
+// unless overridden, e.g. with "bit=[]byte".
+func typeKey(fieldType *types.FieldType) string {
+	key := types.TypeToStr(fieldType.Tp, fieldType.Charset)
+	if fieldType.Tp == mysql.TypeBit && fieldType.Flen <= 1 {
+		return "bit(1)"
+	}
+	if mysql.HasUnsignedFlag(fieldType.Flag) && tidbtypes.IsTypeNumeric(fieldType.Tp) {
+		key += " unsigned"
+	}
+	return key
+}
+
+// isBinaryType reports whether fieldType holds bytes rather than characters.
+// String types whose charset is binary are byte strings (BINARY, VARBINARY,
+// BLOB), while a binary collation on a text charset, as in VARCHAR(n) BINARY,
+// still holds characters.
+func isBinaryType(fieldType *types.FieldType) bool {
+	switch fieldType.Tp {
+	case mysql.TypeGeometry:
+		return true
+	case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString,
+		mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		return fieldType.Charset == charset.CharsetBin
+	}
+	return false
+}
+
+// isBoolType reports whether fieldType is TINYINT(1), which is what BOOLEAN
+// is an alias for.
+func isBoolType(fieldType *types.FieldType) bool {
+	return fieldType.Tp == mysql.TypeTiny && fieldType.Flen == 1
+}
+
+// SetType overrides the Go type of a MySQL type key, e.g. "int unsigned" or
+// "bit(1)". goType is either a predeclared type or "import/path.Name".
+func (parser *DDLParser) SetType(key string, goType string) error {
+	qualified, err := parser.qualifyType(goType)
+	if err != nil {
+		return errors.Annotatef(err, "type of %s", key)
+	}
+	parser.typeMap[strings.ToLower(key)] = qualified
+	return nil
+}
+
+// SetDecimalType sets the Go type of DECIMAL columns to one of DecimalTypes.
+func (parser *DDLParser) SetDecimalType(name string) error {
+	goType, ok := DecimalTypes[name]
+	if !ok {
+		return errors.Errorf("unknown decimal type :%s", name)
+	}
+	for _, key := range []string{"decimal", "decimal unsigned"} {
+		if err := parser.SetType(key, goType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetTimeType sets the Go type of DATE, DATETIME and TIMESTAMP columns to one
+// of TimeTypes.
+func (parser *DDLParser) SetTimeType(name string) error {
+	goType, ok := TimeTypes[name]
+	if !ok {
+		return errors.Errorf("unknown time type :%s", name)
+	}
+	for _, key := range []string{"date", "datetime", "timestamp"} {
+		if err := parser.SetType(key, goType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// qualifyType turns "import/path.Name" into "alias.Name" and records the
+// import the generated code will need. Pointer and slice prefixes are kept.
+func (parser *DDLParser) qualifyType(goType string) (string, error) {
+	name := strings.TrimLeft(goType, "*[]")
+	prefix := goType[:len(goType)-len(name)]
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 {
+		return goType, nil
+	}
+	if dot == 0 || dot == len(name)-1 || strings.HasSuffix(name[:dot], "/") {
+		return "", errors.Errorf("type must look like import/path.Name :%s", goType)
+	}
+	importPath := name[:dot]
+	alias := path.Base(importPath)
+	parser.typeImports[alias] = importPath
+	return prefix + alias + name[dot:], nil
+}
+
+func (parser *DDLParser) getColumnType(fieldType *types.FieldType) string {
+	if parser.TinyIntAsBool && isBoolType(fieldType) {
+		return "bool"
+	}
+	if goType, ok := parser.typeMap[typeKey(fieldType)]; ok {
+		return goType
+	}
+	return "string"
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/types"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/dbtypes"
+)
+
+// HasValidate reports whether a Validate method is generated for the table:
+// when a column is validated against its type or a check is translated to Go.
+func (table *Table) HasValidate() bool {
+	for _, column := range table.Columns {
+		if column.ValidateType != "" {
+			return true
+		}
+	}
+	for _, check := range table.Checks {
+		if check.Cond != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateType returns the dbtypes.ColumnType the values of column are
+// validated against, as a Go expression. Generated columns, which are never
+// written, columns of user-defined types and JSON columns are not validated.
+func validateType(column Column) string {
+	ft := column.FieldType
+	// blob and binary rather than text and char, for binary strings
+	typeName := types.TypeToStr(ft.Tp, columnCharset(ft))
+	if column.Generated != "" || column.Serializer != "" || !dbtypes.CanValidate(typeName) {
+		return ""
+	}
+	if _, ok := column.Annotations["type"]; ok {
+		return ""
+	}
+	length, decimal := ft.Flen, ft.Decimal
+	defaultLength, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(ft.Tp)
+	if length == types.UnspecifiedLength {
+		length = defaultLength
+	}
+	if decimal == types.UnspecifiedLength {
+		decimal = defaultDecimal
+	}
+	fields := []string{
+		fmt.Sprintf("Type: %q", typeName),
+		fmt.Sprintf("Length: %d", length),
+		fmt.Sprintf("Decimal: %d", decimal),
+	}
+	if mysql.HasUnsignedFlag(ft.Flag) {
+		fields = append(fields, "Unsigned: true")
+	}
+	if !column.NotNull {
+		fields = append(fields, "Nullable: true")
+	}
+	switch ft.Tp {
+	case mysql.TypeString, mysql.TypeVarchar,
+		mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		fields = append(fields, fmt.Sprintf("Charset: %q", columnCharset(ft)))
+	case mysql.TypeEnum, mysql.TypeSet:
+		elems := make([]string, len(ft.Elems))
+		for i, elem := range ft.Elems {
+			elems[i] = fmt.Sprintf("%q", elem)
+		}
+		fields = append(fields,
+			fmt.Sprintf("Elems: []string{%s}", strings.Join(elems, ", ")),
+			fmt.Sprintf("Collation: %q", effectiveCollation(ft)))
+	}
+	return fmt.Sprintf("dbtypes.ColumnType{%s}", strings.Join(fields, ", "))
+}
+
+// columnCharset returns the charset lengths of a string column are counted
+// in, utf8mb4 when the DDL does not say.
+func columnCharset(ft *types.FieldType) string {
+	switch {
+	case ft.Charset != "":
+		return ft.Charset
+	case ft.Collate == charset.CollationBin:
+		return charset.CharsetBin
+	case ft.Collate != "":
+		if i := strings.IndexByte(ft.Collate, '_'); i > 0 {
+			return ft.Collate[:i]
+		}
+	}
+	return mysql.UTF8MB4Charset
+}
@@ -0,0 +1,34 @@
+package parser
+
+import "testing"
+
+func TestValidateType(t *testing.T) {
+	parser := New("test.sql", "", "test")
+	parser.Validate = true
+	table := tableOf(t, parseSQL(t, parser, `CREATE TABLE t (
+		b blob NOT NULL,
+		vb varbinary(8) NOT NULL,
+		tx text NOT NULL,
+		c char(4) CHARACTER SET latin1 NOT NULL,
+		y year NOT NULL,
+		f float,
+		g int AS (1)
+	)`), "t")
+	for _, test := range []struct {
+		column string
+		expr   string
+	}{
+		{"b", `dbtypes.ColumnType{Type: "blob", Length: 65535, Decimal: 0, Charset: "binary"}`},
+		{"vb", `dbtypes.ColumnType{Type: "varbinary", Length: 8, Decimal: 0, Charset: "binary"}`},
+		{"tx", `dbtypes.ColumnType{Type: "text", Length: 65535, Decimal: 0, Charset: "utf8mb4"}`},
+		{"c", `dbtypes.ColumnType{Type: "char", Length: 4, Decimal: 0, Charset: "latin1"}`},
+		{"y", `dbtypes.ColumnType{Type: "year", Length: 4, Decimal: 0}`},
+		{"f", `dbtypes.ColumnType{Type: "float", Length: 12, Decimal: -1, Nullable: true}`},
+		// Generated columns are never written.
+		{"g", ``},
+	} {
+		if got := columnOf(t, table, test.column).ValidateType; got != test.expr {
+			t.Errorf("%s: got %s, want %s", test.column, got, test.expr)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package tpl
+
+import (
+	"sort"
+
+	"github.com/pingcap/errors"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/parser"
+)
+
+// Mode tells what a template renders: an output file of the routing, a table
+// or the whole schema.
+type Mode string
+
+const (
+	// ModeFile renders every output file, with the tables routed to it.
+	ModeFile Mode = "file"
+	// ModeTable renders every table on its own, in <table>.go by default.
+	ModeTable Mode = "table"
+	// ModeSchema renders all the tables at once.
+	ModeSchema Mode = "schema"
+)
+
+// ParseMode validates a render mode given on the command line.
+func ParseMode(name string) (Mode, error) {
+	switch mode := Mode(name); mode {
+	case "":
+		return ModeFile, nil
+	case ModeFile, ModeTable, ModeSchema:
+		return mode, nil
+	}
+	return "", errors.Errorf("unknown render mode :%s", name)
+}
+
+// TemplateVar is the data templates are executed with. Tables are
+// *parser.Table values; see the README for the fields and methods templates
+// may rely on.
+type TemplateVar struct {
+	Mode        Mode
+	InputFile   string   // first of InputFiles
+	InputFiles  []string // inputs that created or changed the tables, in the order they are read
+	OutputFile  string   // file being generated
+	PackageName string
+	Imports     map[string]string        // alias -> import path of the types of the tables
+	Structs     map[string]*parser.Table // by table name
+	Tables      []*parser.Table          // Structs, ordered by table name
+	Table       *parser.Table            // the table being rendered, in ModeTable
+	WithTag     bool
+	// WithConstructor generates a New function per table applying the
+	// column defaults.
+	WithConstructor bool
+	TagString       string
+	FileContent     string
+	// Relationships are the foreign keys of all the tables of the session,
+	// resolved across input files; see RelationshipsOf.
+	Relationships []parser.Relationship
+}
+
+// NewTemplateVar returns the data of the tables structs, sorting them into
+// Tables.
+func NewTemplateVar(mode Mode, structs map[string]*parser.Table) TemplateVar {
+	tables := make([]*parser.Table, 0, len(structs))
+	for _, table := range structs {
+		tables = append(tables, table)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].TableName < tables[j].TableName
+	})
+	data := TemplateVar{
+		Mode:    mode,
+		Structs: structs,
+		Tables:  tables,
+		WithTag: true,
+	}
+	if mode == ModeTable && len(tables) == 1 {
+		data.Table = tables[0]
+	}
+	return data
+}
+
+// RelationshipsOf returns the Relationships in which table references
+// another table or is referenced.
+func (data TemplateVar) RelationshipsOf(table *parser.Table) []parser.Relationship {
+	var relationships []parser.Relationship
+	for _, relationship := range data.Relationships {
+		if relationship.Table == table || relationship.RefTable == table {
+			relationships = append(relationships, relationship)
+		}
+	}
+	return relationships
+}
@@ -0,0 +1,120 @@
+package tpl
+
+// EnumTemplate renders the Go type of an ENUM or SET column, given its parser.Enum.
+var EnumTemplate = `
+{{- define "enum" }}
+{{- $type := .TypeName }}
+{{- $elems := printf "%sElems" (Unexported $type) }}
+{{- if .IsSet }}
+
+// {{ $type }} is a SET value, one bit per element.
+type {{ $type }} uint64
+
+const (
+	{{- range $i, $elem := .Elems }}
+	{{ $elem.ConstName }} {{- if eq $i 0 }} {{ $type }} = 1 << iota {{- end }}
+	{{- end }}
+)
+
+var {{ $elems }} = []string{ {{- range .Elems }}{{ Quote .Value }}, {{ end -}} }
+
+// {{ $type }}Of returns the {{ $type }} holding the elements of the given 1-based ordinals.
+func {{ $type }}Of(ordinals ...int) ({{ $type }}, error) {
+	bits, err := dbtypes.SetOfOrdinals({{ $elems }}, ordinals...)
+	return {{ $type }}(bits), err
+}
+
+func (s {{ $type }}) Has(flag {{ $type }}) bool {
+	return s&flag == flag
+}
+
+func (s *{{ $type }}) Add(flag {{ $type }}) {
+	*s |= flag
+}
+
+func (s *{{ $type }}) Remove(flag {{ $type }}) {
+	*s &^= flag
+}
+
+// Names returns the elements of s, in declaration order.
+func (s {{ $type }}) Names() []string {
+	return dbtypes.SetNames({{ $elems }}, uint64(s))
+}
+
+// Ordinals returns the 1-based ordinals of the elements of s.
+func (s {{ $type }}) Ordinals() []int {
+	return dbtypes.SetOrdinals({{ $elems }}, uint64(s))
+}
+
+func (s {{ $type }}) IsValid() bool {
+	return dbtypes.IsValidSet({{ $elems }}, uint64(s))
+}
+
+func (s {{ $type }}) String() string {
+	name, _ := dbtypes.SetName({{ $elems }}, uint64(s))
+	return name
+}
+
+func (s *{{ $type }}) Scan(src interface{}) error {
+	bits, err := dbtypes.ScanSet({{ $elems }}, {{ Quote .Collation }}, src)
+	*s = {{ $type }}(bits)
+	return err
+}
+
+func (s {{ $type }}) Value() (driver.Value, error) {
+	return dbtypes.SetValue({{ $elems }}, uint64(s))
+}
+
+func (s {{ $type }}) MarshalJSON() ([]byte, error) {
+	return dbtypes.MarshalSet({{ $elems }}, uint64(s))
+}
+
+func (s *{{ $type }}) UnmarshalJSON(data []byte) error {
+	bits, err := dbtypes.UnmarshalSet({{ $elems }}, {{ Quote .Collation }}, data)
+	*s = {{ $type }}(bits)
+	return err
+}
+{{- else }}
+
+// {{ $type }} is an ENUM value.
+type {{ $type }} string
+
+const (
+	{{- range .Elems }}
+	{{ .ConstName }} {{ $type }} = {{ Quote .Value }}
+	{{- end }}
+)
+
+var {{ $elems }} = []string{ {{- range .Elems }}{{ Quote .Value }}, {{ end -}} }
+
+// IsValid reports whether e is an element of the ENUM, compared as MySQL does.
+func (e {{ $type }}) IsValid() bool {
+	return dbtypes.IsValidEnum({{ $elems }}, {{ Quote .Collation }}, string(e))
+}
+
+func (e {{ $type }}) String() string {
+	return string(e)
+}
+
+func (e *{{ $type }}) Scan(src interface{}) error {
+	name, err := dbtypes.ScanEnum({{ $elems }}, {{ Quote .Collation }}, src)
+	*e = {{ $type }}(name)
+	return err
+}
+
+func (e {{ $type }}) Value() (driver.Value, error) {
+	return dbtypes.EnumValue({{ $elems }}, {{ Quote .Collation }}, string(e))
+}
+
+func (e {{ $type }}) MarshalJSON() ([]byte, error) {
+	return dbtypes.MarshalEnum(string(e))
+}
+
+func (e *{{ $type }}) UnmarshalJSON(data []byte) error {
+	name, err := dbtypes.UnmarshalEnum({{ $elems }}, {{ Quote .Collation }}, data)
+	*e = {{ $type }}(name)
+	return err
+}
+{{- end }}
+{{- end }}
+`
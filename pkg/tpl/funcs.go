@@ -0,0 +1,64 @@
+package tpl
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+	"github.com/pingcap/errors"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/naming"
+	"github.com/Sterrenhemel/ddl2struct/pkg/parser"
+)
+
+// Funcs are the functions templates may call, the built-in ones included.
+var Funcs = template.FuncMap{
+	"mapExists":    mapExists,
+	"ToCamel":      strcase.ToCamel,
+	"ToLowerCamel": strcase.ToLowerCamel,
+	"ToSnake":      strcase.ToSnake,
+	"ToKebab":      strcase.ToKebab,
+	"Pluralize":    parser.Pluralize,
+	"Singularize":  parser.Singularize,
+	"GoIdent":      naming.Escape,
+	"Unexported":   naming.Namer{}.Unexported,
+	"Tag":          Tag,
+	"Quote":        strconv.Quote,
+	"Base":         path.Base,
+	"Join":         strings.Join,
+	"Lower":        strings.ToLower,
+	"Upper":        strings.ToUpper,
+	"HasPrefix":    strings.HasPrefix,
+	"HasSuffix":    strings.HasSuffix,
+	"TrimPrefix":   strings.TrimPrefix,
+	"TrimSuffix":   strings.TrimSuffix,
+	"Replace":      strings.ReplaceAll,
+}
+
+func mapExists(v TemplateVar) bool {
+	if v.Imports == nil || len(v.Imports) == 0 {
+		return false
+	}
+	return true
+}
+
+// Tag returns a struct tag, backquotes included, from key and value pairs,
+// e.g. Tag "json" "name" "db" "name" gives `json:"name" db:"name"`. Pairs
+// with an empty value are left out, and so is the tag when all are.
+func Tag(pairs ...string) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", errors.Errorf("Tag takes key and value pairs, got %d arguments", len(pairs))
+	}
+	var tags []string
+	for i := 0; i < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			tags = append(tags, pairs[i]+":"+strconv.Quote(pairs[i+1]))
+		}
+	}
+	if len(tags) == 0 {
+		return "", nil
+	}
+	return "`" + strings.Join(tags, " ") + "`", nil
+}
@@ -0,0 +1,130 @@
+package tpl
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pingcap/errors"
+)
+
+// builtinName is the name of the built-in template, as if it were a file.
+const builtinName = "tables.go.tmpl"
+
+// Template is a template to render, built-in or loaded from a file.
+type Template struct {
+	Name string // file name, e.g. "model.go.tmpl"
+	t    *template.Template
+}
+
+// Builtin returns the built-in template, TableTemplate.
+func Builtin() *Template {
+	t := template.Must(newTemplate(builtinName).Parse(TableTemplate))
+	return &Template{Name: builtinName, t: t}
+}
+
+func newTemplate(name string) *template.Template {
+	return template.Must(template.New(name).Funcs(Funcs).Parse(EnumTemplate))
+}
+
+// Load loads the template files and the *.tmpl files of dir, in name order.
+// The files of dir whose name starts with _ are partials: they define
+// templates the others may call, and are not rendered themselves. The "enum"
+// template of EnumTemplate may be called too.
+func Load(files []string, dir string) ([]*Template, error) {
+	var partials []string
+	if dir != "" {
+		matches, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			if strings.HasPrefix(filepath.Base(match), "_") {
+				partials = append(partials, match)
+			} else {
+				files = append(files, match)
+			}
+		}
+		if len(files) == 0 {
+			return nil, errors.Errorf("no template in %s", dir)
+		}
+	}
+	templates := make([]*Template, 0, len(files))
+	names := make(map[string]string)
+	for _, file := range files {
+		name := filepath.Base(file)
+		if other, ok := names[name]; ok {
+			return nil, errors.Errorf("templates %s and %s have the same name", other, file)
+		}
+		names[name] = file
+		t := newTemplate(name)
+		for _, partial := range append(partials, file) {
+			text, err := ioutil.ReadFile(partial)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if _, err := t.New(filepath.Base(partial)).Parse(string(text)); err != nil {
+				return nil, errors.Annotatef(err, "template %s", partial)
+			}
+		}
+		templates = append(templates, &Template{Name: name, t: t.Lookup(name)})
+	}
+	return templates, nil
+}
+
+// FileName returns the file t renders data to. A template may name it by
+// defining a "filename" template, executed with data, whose relative paths
+// are in dir. Otherwise it is base in dir, with the extension of the template
+// name once .tmpl is removed, .go by default.
+func (t *Template) FileName(data TemplateVar, dir, base string) (string, error) {
+	name := strings.TrimSuffix(t.Name, ".tmpl")
+	ext := path.Ext(name)
+	if ext == "" {
+		ext = ".go"
+	}
+	fileName := base + ext
+	if named := t.t.Lookup("filename"); named != nil {
+		var buf bytes.Buffer
+		if err := named.Execute(&buf, data); err != nil {
+			return "", errors.Annotatef(err, "template %s", t.Name)
+		}
+		fileName = strings.TrimSpace(buf.String())
+		if fileName == "" {
+			return "", errors.Errorf("template %s: empty filename", t.Name)
+		}
+	}
+	if filepath.IsAbs(fileName) {
+		return fileName, nil
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// Stem returns the name of the template without its extensions, e.g. model
+// for model.go.tmpl.
+func (t *Template) Stem() string {
+	name := strings.TrimSuffix(t.Name, ".tmpl")
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// Execute renders data into fileName. Go files are formatted.
+func (t *Template) Execute(fileName string, data TemplateVar) ([]byte, error) {
+	data.OutputFile = fileName
+	var buf bytes.Buffer
+	if err := t.t.Execute(&buf, data); err != nil {
+		return nil, errors.Annotatef(err, "template %s", t.Name)
+	}
+	if filepath.Ext(fileName) != ".go" {
+		return buf.Bytes(), nil
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.Annotatef(err, "template %s renders invalid Go code for %s", t.Name, fileName)
+	}
+	return source, nil
+}
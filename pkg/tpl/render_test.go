@@ -0,0 +1,209 @@
+package tpl
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/Sterrenhemel/ddl2struct/pkg/parser"
+)
+
+// writeTemplates writes the files, by name, to a new directory and returns it.
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, text := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParseMode(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		mode Mode
+		err  string
+	}{
+		{"", ModeFile, ""},
+		{"file", ModeFile, ""},
+		{"table", ModeTable, ""},
+		{"schema", ModeSchema, ""},
+		{"tables", "", "unknown render mode :tables"},
+	} {
+		mode, err := ParseMode(test.name)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%q: got %v, want %s", test.name, err, test.err)
+			}
+		} else if err != nil || mode != test.mode {
+			t.Errorf("%q: got %s, %v, want %s", test.name, mode, err, test.mode)
+		}
+	}
+}
+
+func TestNewTemplateVar(t *testing.T) {
+	users := &parser.Table{TableName: "users"}
+	orders := &parser.Table{TableName: "orders"}
+	for _, test := range []struct {
+		mode    Mode
+		structs map[string]*parser.Table
+		tables  []*parser.Table
+		table   *parser.Table
+	}{
+		{ModeFile, map[string]*parser.Table{"users": users, "orders": orders}, []*parser.Table{orders, users}, nil},
+		{ModeSchema, map[string]*parser.Table{"users": users}, []*parser.Table{users}, nil},
+		{ModeTable, map[string]*parser.Table{"users": users}, []*parser.Table{users}, users},
+	} {
+		data := NewTemplateVar(test.mode, test.structs)
+		if !data.WithTag || data.Mode != test.mode || data.Table != test.table {
+			t.Errorf("%s: got mode %s, table %v, with tag %v", test.mode, data.Mode, data.Table, data.WithTag)
+		}
+		if len(data.Tables) != len(test.tables) {
+			t.Errorf("%s: got %d tables, want %d", test.mode, len(data.Tables), len(test.tables))
+			continue
+		}
+		for i, table := range data.Tables {
+			if table != test.tables[i] {
+				t.Errorf("%s: table %d: got %s, want %s", test.mode, i, table.TableName, test.tables[i].TableName)
+			}
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"_header.tmpl":    `{{ define "header" }}// Package {{ .PackageName }} is generated.{{ end }}`,
+		"model.go.tmpl":   "{{ template \"header\" . }}\npackage {{ .PackageName }}\nvar   tables = {{ len .Tables }}\n",
+		"schema.sql.tmpl": `{{ define "filename" }}sql/{{ .PackageName }}.sql{{ end }}-- {{ range .Tables }}{{ .TableName }} {{ end }}`,
+		"README.md":       "not a template",
+	})
+	templates, err := Load(nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 || templates[0].Name != "model.go.tmpl" || templates[1].Name != "schema.sql.tmpl" {
+		t.Fatalf("got templates %v", templates)
+	}
+	data := NewTemplateVar(ModeSchema, map[string]*parser.Table{"users": {TableName: "users"}})
+	data.PackageName = "models"
+	for _, test := range []struct {
+		template *Template
+		stem     string
+		fileName string
+		content  string
+	}{
+		{templates[0], "model", filepath.Join("out", "models.go"), "// Package models is generated.\npackage models\n\nvar tables = 1\n"},
+		{templates[1], "schema", filepath.Join("out", "sql", "models.sql"), "-- users "},
+	} {
+		if stem := test.template.Stem(); stem != test.stem {
+			t.Errorf("%s: got stem %s, want %s", test.template.Name, stem, test.stem)
+		}
+		fileName, err := test.template.FileName(data, "out", "models")
+		if err != nil || fileName != test.fileName {
+			t.Errorf("%s: got file %s, %v, want %s", test.template.Name, fileName, err, test.fileName)
+		}
+		content, err := test.template.Execute(fileName, data)
+		if err != nil || string(content) != test.content {
+			t.Errorf("%s: got %q, %v, want %q", test.template.Name, content, err, test.content)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		files map[string]string
+		err   string
+	}{
+		{"no template", map[string]string{"_partial.tmpl": ""}, "no template in"},
+		{"parse error", map[string]string{"model.go.tmpl": "{{ .Tables "}, "template "},
+		{"partial error", map[string]string{"_partial.tmpl": "{{ end }}", "model.go.tmpl": ""}, "_partial.tmpl"},
+	} {
+		_, err := Load(nil, writeTemplates(t, test.files))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got %v, want %s", test.name, err, test.err)
+		}
+	}
+	a := writeTemplates(t, map[string]string{"model.go.tmpl": ""})
+	b := writeTemplates(t, map[string]string{"model.go.tmpl": ""})
+	if _, err := Load([]string{filepath.Join(a, "model.go.tmpl")}, b); err == nil || !strings.Contains(err.Error(), "have the same name") {
+		t.Errorf("same name: got %v", err)
+	}
+}
+
+func TestFileNameErrors(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"empty.go.tmpl":   `{{ define "filename" }} {{ end }}`,
+		"invalid.go.tmpl": "package {{ .PackageName }}\nfunc {",
+	})
+	templates, err := Load(nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := NewTemplateVar(ModeFile, nil)
+	data.PackageName = "models"
+	if _, err := templates[0].FileName(data, "out", "models"); err == nil || err.Error() != "template empty.go.tmpl: empty filename" {
+		t.Errorf("empty filename: got %v", err)
+	}
+	if _, err := templates[1].Execute("models.go", data); err == nil || !strings.Contains(err.Error(), "renders invalid Go code for models.go") {
+		t.Errorf("invalid Go code: got %v", err)
+	}
+	// Only Go files are formatted.
+	if content, err := templates[1].Execute("models.txt", data); err != nil || string(content) != "package models\nfunc {" {
+		t.Errorf("text file: got %q, %v", content, err)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	for _, test := range []struct {
+		text string
+		want string
+	}{
+		{`{{ ToCamel "user_name" }}`, "UserName"},
+		{`{{ ToLowerCamel "user_name" }}`, "userName"},
+		{`{{ ToSnake "UserName" }}`, "user_name"},
+		{`{{ ToKebab "UserName" }}`, "user-name"},
+		{`{{ Pluralize "category" }}`, "categories"},
+		{`{{ Singularize "users" }}`, "user"},
+		{`{{ GoIdent "type" }}`, "type_"},
+		{`{{ Unexported "UserID" }}`, "userID"},
+		{`{{ Tag "json" "name" "db" "" "xml" "n" }}`, "`json:\"name\" xml:\"n\"`"},
+		{`{{ Tag "db" "" }}`, ""},
+		{`{{ Quote "it's \"q\"" }}`, `"it's \"q\""`},
+		{`{{ Base "github.com/google/uuid" }}`, "uuid"},
+		{`{{ Join .Values ", " }}`, "a, b"},
+		{`{{ Lower "ID" }} {{ Upper "id" }}`, "id ID"},
+		{`{{ HasPrefix "t_users" "t_" }} {{ HasSuffix "users" "s" }}`, "true true"},
+		{`{{ TrimPrefix "t_users" "t_" }} {{ TrimSuffix "users_bak" "_bak" }}`, "users users"},
+		{`{{ Replace "a-b-c" "-" "_" }}`, "a_b_c"},
+	} {
+		tmpl, err := template.New("test").Funcs(Funcs).Parse(test.text)
+		if err != nil {
+			t.Errorf("%s: %v", test.text, err)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, map[string][]string{"Values": {"a", "b"}}); err != nil {
+			t.Errorf("%s: %v", test.text, err)
+		} else if buf.String() != test.want {
+			t.Errorf("%s: got %s, want %s", test.text, buf.String(), test.want)
+		}
+	}
+	if _, err := Tag("json"); err == nil || err.Error() != "Tag takes key and value pairs, got 1 arguments" {
+		t.Errorf("odd Tag arguments: got %v", err)
+	}
+}
+
+func TestMapExists(t *testing.T) {
+	if mapExists(TemplateVar{}) || mapExists(TemplateVar{Imports: map[string]string{}}) {
+		t.Error("got imports without any")
+	}
+	if !mapExists(TemplateVar{Imports: map[string]string{"uuid": "github.com/google/uuid"}}) {
+		t.Error("got no imports")
+	}
+}
@@ -1,13 +1,17 @@
 package tpl
 
 var TableTemplate = `// Code generated by DDL2STRUCT. DO NOT EDIT.
+{{- if gt (len .InputFiles) 1 }}
+// InputFiles: {{ Join .InputFiles ", " }}
+{{- else }}
 // InputFile: {{ .InputFile }}
+{{- end }}
 package {{ .PackageName}}
 
 {{- if mapExists . }}
 import (
 	{{- range $alias, $path := .Imports}}
-	{{- if eq $alias $path }}
+	{{- if eq $alias (Base $path) }}
 	"{{$path}}"
 	{{- else }}
 	{{$alias }}"{{$path}}"
@@ -19,14 +23,71 @@ import (
 
 {{- range $tableName, $table := .Structs}}
 {{ if $table.TableComment }} // {{ $table.TableComment }} {{- end}}
-type {{ ToCamel $tableName }} struct{
+type {{ $table.StructName }} struct{
 	{{- range $idx, $column := $table.Columns}}
-	{{ ToCamel $column.Name }} {{ $column.Type }}  ` + "`json:\"{{ ToSnake $column.Name }}\" gorm:\"column:{{ $column.Name }}\"`" + `{{ if $column.Comment }}// {{ $column.Comment }}  {{- end}}
+	{{ $column.FieldName }} {{ $column.Type }} {{ with $table.StructTag $column }} ` + "`{{ . }}`" + `{{ end }}{{ if $column.Comment }}// {{ $column.Comment }}  {{- end}}
+	{{- end}}
+	{{- range $table.Associations }}
+	{{ .Name }} {{ .Type }} {{ with .StructTag }} ` + "`{{ . }}`" + `{{ end }}
 	{{- end}}
 }
 
-func ({{ ToCamel $tableName }}) TableName() string {
+func ({{ $table.StructName }}) TableName() string {
 	return "{{ $tableName }}"
 }
+{{- if $table.HasValidate }}
+
+// Validate returns an error when MySQL would reject t: a value its column
+// cannot store, or a violated CHECK constraint of the DDL.
+func (t {{ $table.StructName }}) Validate() error {
+	{{- range $table.Columns }}
+	{{- if .ValidateType }}
+	if err := dbtypes.ValidateColumn({{ Quote .Name }}, t.{{ .FieldName }}, {{ .ValidateType }}); err != nil {
+		return err
+	}
+	{{- end }}
+	{{- end }}
+	{{- range $table.Checks }}
+	{{- if .Cond }}
+	if {{ .Cond }}.IsFalse() {
+		return &dbtypes.CheckError{Constraint: {{ Quote .Name }}, Expr: {{ Quote .Expr }}}
+	}
+	{{- end }}
+	{{- end }}
+	return nil
+}
+{{- end }}
+{{- range $idx, $column := $table.Columns}}
+{{- with $column.JSONExtract }}
+{{- if .Accessor }}
+
+// {{ .Accessor }} evaluates {{ .Path }} on {{ .Field }}, as MySQL computes the generated column {{ $column.Name }}.
+func (t {{ $table.StructName }}) {{ .Accessor }}() ({{ if .Unquote }}string{{ else }}dbtypes.JSON{{ end }}, bool, error) {
+	{{- if .NullCheck }}
+	if {{ .NullCheck }} {
+		return {{ if .Unquote }}""{{ else }}dbtypes.JSON{}{{ end }}, false, nil
+	}
+	{{- end }}
+	return {{ .Value }}.{{ if .Unquote }}GetUnquoted{{ else }}Get{{ end }}({{ Quote .Path }})
+}
+{{- end }}
+{{- end }}
+{{- if $column.Enum }}
+{{ template "enum" $column.Enum }}
+{{- end}}
+{{- end}}
+{{- if $.WithConstructor }}
+
+// New{{ $table.StructName }} returns a {{ $table.StructName }} holding the column defaults of the DDL.
+func New{{ $table.StructName }}() {{ $table.StructName }} {
+	return {{ $table.StructName }}{
+		{{- range $idx, $column := $table.Columns}}
+		{{- if $column.DefaultExpr }}
+		{{ $column.FieldName }}: {{ $column.DefaultExpr }},
+		{{- end}}
+		{{- end}}
+	}
+}
+{{- end}}
 {{- end}}
 `
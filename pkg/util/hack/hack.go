@@ -39,7 +39,9 @@ func String(b []byte) (s MutableString) {
 // Use at your own risk.
 func Slice(s string) (b []byte) {
 	pbytes := (*reflect.SliceHeader)(unsafe.Pointer(&b))
-	pstring := *(*reflect.StringHeader)(unsafe.Pointer(&s))
+	// The header is read through a pointer to s rather than copied, as go vet
+	// requires of reflect.StringHeader values.
+	pstring := (*reflect.StringHeader)(unsafe.Pointer(&s))
 	pbytes.Data = pstring.Data
 	pbytes.Len = pstring.Len
 	pbytes.Cap = pstring.Len
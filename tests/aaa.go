@@ -4,13 +4,13 @@ package tests
 
 //  aaa.go
 type Ddl2Struct struct {
-	PersonId  int64  `json:"person_id" gorm:"column:person_id"`   // "ID"
-	It        int64  `json:"it" gorm:"column:it"`                 // "id"
-	Tit       int8   `json:"tit" gorm:"column:tit"`               // "tinyint"
-	LastName  string `json:"last_name" gorm:"column:last_name"`   // "last Name"
-	FirstName string `json:"first_name" gorm:"column:first_name"` // "first Name"
-	Address   string `json:"address" gorm:"column:address"`       // "address"
-	City      string `json:"city" gorm:"column:city"`             // "city"
+	PersonId  int64  `json:"person_id" gorm:"column:person_id;type:bigint(20)"`              // ID
+	It        int32  `json:"it" gorm:"column:it;type:int(11)"`                               // id
+	Tit       int8   `json:"tit" gorm:"column:tit;type:tinyint(4)"`                          // tinyint
+	LastName  string `json:"last_name" gorm:"column:last_name;type:varchar(255);size:255"`   // last Name
+	FirstName string `json:"first_name" gorm:"column:first_name;type:varchar(255);size:255"` // first Name
+	Address   string `json:"address" gorm:"column:address;type:varchar(255);size:255"`       // address
+	City      string `json:"city" gorm:"column:city;type:varchar(255);size:255"`             // city
 }
 
 func (Ddl2Struct) TableName() string {
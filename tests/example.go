@@ -4,11 +4,11 @@ package tests
 
 // 北极星权限角色表
 type AdminRole struct {
-	Id          int64  `json:"id" gorm:"column:id"`                   // "唯一id"
-	RoleKey     string `json:"role_key" gorm:"column:role_key"`       // "角色key"
-	Description string `json:"description" gorm:"column:description"` // "角色描述"
-	Status      int8   `json:"status" gorm:"column:status"`           // "角色状态"
-	Name        string `json:"name" gorm:"column:name"`               // "角色名称"
+	Id          int64  `json:"id" gorm:"column:id;type:bigint(20);primaryKey;not null"`                         // 唯一id
+	RoleKey     string `json:"role_key" gorm:"column:role_key;type:varchar(255);size:255;uniqueIndex:role_key"` // 角色key
+	Description string `json:"description" gorm:"column:description;type:varchar(255);size:255"`                // 角色描述
+	Status      int8   `json:"status" gorm:"column:status;type:tinyint(1)"`                                     // 角色状态
+	Name        string `json:"name" gorm:"column:name;type:varchar(255);size:255"`                              // 角色名称
 }
 
 func (AdminRole) TableName() string {
@@ -17,8 +17,8 @@ func (AdminRole) TableName() string {
 
 // 北极星角色权限关联表
 type AdminRolePermissionRelation struct {
-	RoleId       int64 `json:"role_id" gorm:"column:role_id"`             // "角色id"
-	PermissionId int64 `json:"permission_id" gorm:"column:permission_id"` // "权限id"
+	RoleId       int64 `json:"role_id" gorm:"column:role_id;type:bigint(20);uniqueIndex:role_permission_uk,priority:1"`             // 角色id
+	PermissionId int64 `json:"permission_id" gorm:"column:permission_id;type:bigint(20);uniqueIndex:role_permission_uk,priority:2"` // 权限id
 }
 
 func (AdminRolePermissionRelation) TableName() string {
@@ -26,11 +26,11 @@ func (AdminRolePermissionRelation) TableName() string {
 }
 
 type Ddl2Struct2 struct {
-	PersonId  int64  `json:"person_id" gorm:"column:person_id"`
-	LastName  string `json:"last_name" gorm:"column:last_name"`
-	FirstName string `json:"first_name" gorm:"column:first_name"`
-	Address   string `json:"address" gorm:"column:address"`
-	City      string `json:"city" gorm:"column:city"`
+	PersonId  int64  `json:"person_id" gorm:"column:person_id;type:bigint(20)"`
+	LastName  string `json:"last_name" gorm:"column:last_name;type:varchar(255);size:255"`
+	FirstName string `json:"first_name" gorm:"column:first_name;type:varchar(255);size:255"`
+	Address   string `json:"address" gorm:"column:address;type:varchar(255);size:255"`
+	City      string `json:"city" gorm:"column:city;type:varchar(255);size:255"`
 }
 
 func (Ddl2Struct2) TableName() string {